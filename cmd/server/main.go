@@ -1,17 +1,37 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/rh-utcp/rh-utcp/internal/config"
+	"github.com/rh-utcp/rh-utcp/internal/middleware"
 	"github.com/rh-utcp/rh-utcp/internal/providers"
 	"github.com/rh-utcp/rh-utcp/internal/providers/gitlab"
 	"github.com/rh-utcp/rh-utcp/internal/providers/jira"
+	"github.com/rh-utcp/rh-utcp/internal/providers/kubernetes"
+	"github.com/rh-utcp/rh-utcp/internal/providers/remote"
+	"github.com/rh-utcp/rh-utcp/internal/providers/rest"
+	"github.com/rh-utcp/rh-utcp/internal/providers/testrail"
 	"github.com/rh-utcp/rh-utcp/internal/providers/wiki"
+	"github.com/rh-utcp/rh-utcp/pkg/circuit"
 	"github.com/rh-utcp/rh-utcp/pkg/errors"
 	"github.com/rh-utcp/rh-utcp/pkg/logger"
+	"github.com/rh-utcp/rh-utcp/pkg/openapi"
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
 )
 
@@ -19,8 +39,28 @@ var (
 	cfg      *config.Config
 	registry *providers.Registry
 	log      logger.Logger
+	audit    *logger.AuditLogger
+
+	// startTime is used to report uptime on /health
+	startTime = time.Now()
+
+	// ready is set once providers have been created; until then discovery
+	// returns 503 so agents do not cache an empty manual
+	ready atomic.Bool
+)
+
+// Build metadata, set at build time with
+// -ldflags "-X main.version=... -X main.gitCommit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	gitCommit = "unknown"
+	buildDate = "unknown"
 )
 
+// notReadyRetryAfter is the Retry-After value, in seconds, sent while the
+// server is still initializing providers
+const notReadyRetryAfter = "1"
+
 func main() {
 	// Initialize logger
 	log = logger.New(logger.Config{
@@ -42,28 +82,63 @@ func main() {
 
 	// Update logger level from config
 	log = logger.New(logger.Config{
-		Level:    cfg.Server.LogLevel,
-		UseColor: true,
+		Level:      cfg.Server.LogLevel,
+		UseColor:   true,
+		SampleRate: cfg.Server.LogSampleRate,
+		RedactKeys: []string{"password", "token", "api_key"},
+		Logfmt:     cfg.Server.LogFormat == "logfmt",
 	})
 	logger.SetGlobal(log.(*logger.StructuredLogger))
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
+	// Validate configuration, reporting every problem at once
+	if err := validateConfig(cfg); err != nil {
 		log.WithError(err).Fatal("Invalid configuration")
 	}
 
+	// Open the discovery audit log if configured
+	if cfg.Server.AuditLogPath != "" {
+		auditFile, err := os.OpenFile(cfg.Server.AuditLogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to open audit log")
+		}
+		defer auditFile.Close()
+		audit = logger.NewAuditLogger(auditFile)
+	}
+
+	// Send access logs to their own file if configured
+	accessLog := log
+	if cfg.Server.AccessLogPath != "" {
+		accessFile, err := os.OpenFile(cfg.Server.AccessLogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to open access log")
+		}
+		defer accessFile.Close()
+		accessLog = logger.New(logger.Config{
+			Level:      "info",
+			Output:     accessFile,
+			UTC:        true,
+			RedactKeys: []string{"password", "token", "api_key"},
+		})
+	}
+
 	// Initialize provider registry
 	registry = providers.NewRegistry()
+	registry.SetMaxConcurrentRefreshes(cfg.Server.MaxConcurrentRefreshes)
+	registry.SetToolCacheTTL(cfg.Server.ToolCacheTTL)
+	registry.SetCreateAttempts(cfg.Server.ProviderCreateAttempts)
+	registry.SetMaxTools(cfg.Server.MaxManualTools)
 
 	// Register provider factories
 	if err := registerProviderFactories(); err != nil {
 		log.WithError(err).Fatal("Failed to register provider factories")
 	}
 
-	// Create providers from configuration
-	if err := createProviders(); err != nil {
-		log.WithError(err).Fatal("Failed to create providers")
-	}
+	// Reload provider configuration on SIGHUP once providers are ready
+	watchReloadSignal()
+
+	// Create providers while the listener starts; discovery answers 503 with
+	// Retry-After until they are ready
+	initProvidersInBackground()
 
 	// Initialize Gin
 	if cfg.Server.Environment == "production" {
@@ -73,24 +148,62 @@ func main() {
 	r := gin.New()
 
 	// Add logging middleware
-	r.Use(ginLogger())
-	r.Use(gin.Recovery())
+	r.Use(ginLogger(accessLog))
+	r.Use(middleware.ErrorDetails(cfg.Server.Environment))
+	r.Use(middleware.Recovery(log))
+
+	// Allow browser-based agents from configured origins
+	if len(cfg.Server.CORSAllowedOrigins) > 0 {
+		r.Use(middleware.CORS(cfg.Server.CORSAllowedOrigins))
+	}
+
+	// Add per-client rate limiting if configured
+	if cfg.Server.RateLimitRPS > 0 {
+		limiter := middleware.NewRateLimiter(cfg.Server.RateLimitRPS, cfg.Server.RateLimitBurst)
+		limiter.StartCleanup(time.Minute)
+		defer limiter.Stop()
+		r.Use(middleware.RateLimit(limiter))
+	}
+
+	// Bound request handling time; health probes have their own deadlines
+	r.Use(middleware.RequestTimeout(cfg.Server.RequestTimeout, "/health"))
 
 	// UTCP discovery endpoint
 	r.GET("/utcp", handleUTCPDiscovery)
+	r.GET("/utcp/checksum", handleUTCPChecksum)
+	r.GET("/utcp/index", handleUTCPIndex)
+	r.GET("/utcp/search", handleUTCPSearch)
+	r.GET("/utcp/tools/:name", handleUTCPTool)
+	r.GET("/utcp/schema/:name", handleUTCPSchema)
+
+	// OpenAPI export of the tool set
+	r.GET("/openapi.json", handleOpenAPI)
+
+	// JSON Schema for validating config.yaml in editors
+	r.GET("/config/schema", handleConfigSchema)
+
+	// Admin endpoints for toggling providers at runtime
+	admin := r.Group("/admin", middleware.AdminAuth(cfg.Server.AdminToken))
+	admin.POST("/providers/:name/enable", handleSetProviderEnabled(true))
+	admin.POST("/providers/:name/disable", handleSetProviderEnabled(false))
+	admin.POST("/loglevel", handleSetLogLevel)
 
-	// Health check endpoint
+	// Health check endpoints
 	r.GET("/health", handleHealth)
+	r.GET("/health/live", handleLiveness)
+	r.GET("/health/ready", handleReadiness)
+
+	// Build metadata
+	r.GET("/version", handleVersion)
 
 	// Start server
 	log.WithFields(map[string]interface{}{
+		"host":        cfg.Server.Host,
 		"port":        cfg.Server.Port,
 		"environment": cfg.Server.Environment,
-		"providers":   len(cfg.Providers),
-		"enabled":     len(registry.GetEnabledProviders()),
 	}).Info("Starting UTCP discovery server")
 
-	if err := r.Run(":" + cfg.Server.Port); err != nil {
+	if err := r.Run(net.JoinHostPort(cfg.Server.Host, cfg.Server.Port)); err != nil {
 		log.WithError(err).Fatal("Failed to start server")
 	}
 }
@@ -114,12 +227,32 @@ func registerProviderFactories() error {
 		return errors.Wrap(err, errors.ErrorTypeConfiguration, "failed to register gitlab factory")
 	}
 
-	log.Debug("Registered provider factories: jira, wiki, confluence, gitlab")
+	// Register TestRail provider factory
+	if err := registry.RegisterFactory("testrail", testrail.NewProviderFromConfig); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfiguration, "failed to register testrail factory")
+	}
+
+	// Register Kubernetes provider factory
+	if err := registry.RegisterFactory("kubernetes", kubernetes.NewProviderFromConfig); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfiguration, "failed to register kubernetes factory")
+	}
+
+	// Register remote tool catalog provider factory
+	if err := registry.RegisterFactory("remote", remote.NewProviderFromConfig); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfiguration, "failed to register remote factory")
+	}
+
+	// Register config-driven REST provider factory
+	if err := registry.RegisterFactory("rest", rest.NewProviderFromConfig); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfiguration, "failed to register rest factory")
+	}
+
+	log.Debug("Registered provider factories: jira, wiki, confluence, gitlab, testrail, kubernetes, remote, rest")
 	return nil
 }
 
-func createProviders() error {
-	for _, providerConfig := range cfg.Providers {
+func createProviders(reg *providers.Registry, providerConfigs []config.ProviderConfig) error {
+	for _, providerConfig := range providerConfigs {
 		// Convert config to map for factory
 		configMap := map[string]interface{}{
 			"name":     providerConfig.Name,
@@ -127,7 +260,17 @@ func createProviders() error {
 			"base_url": providerConfig.BaseURL,
 		}
 
+		// Add provider-specific options without overriding core settings
+		for key, value := range providerConfig.Options {
+			if _, exists := configMap[key]; !exists {
+				configMap[key] = value
+			}
+		}
+
 		// Add auth configuration based on type
+		if providerConfig.Auth.Type != "" {
+			configMap[providers.AuthTypeKey] = providerConfig.Auth.Type
+		}
 		switch providerConfig.Auth.Type {
 		case "basic":
 			configMap["username"] = providerConfig.Auth.Username
@@ -143,11 +286,16 @@ func createProviders() error {
 		}
 
 		// Create provider
-		if err := registry.CreateProvider(providerConfig.Name, providerConfig.Type, configMap); err != nil {
+		if err := reg.CreateProvider(providerConfig.Name, providerConfig.Type, configMap); err != nil {
+			message := "Failed to create provider"
+			if errors.Is(err, errors.ErrorTypeNotFound) {
+				message = "Unknown provider type"
+			}
+
 			log.WithError(err).WithFields(map[string]interface{}{
 				"provider": providerConfig.Name,
 				"type":     providerConfig.Type,
-			}).Error("Failed to create provider")
+			}).Error(message)
 			// Continue with other providers
 		} else {
 			log.WithFields(map[string]interface{}{
@@ -161,52 +309,807 @@ func createProviders() error {
 	return nil
 }
 
-func handleUTCPDiscovery(c *gin.Context) {
+// watchReloadSignal reloads providers whenever the process receives SIGHUP
+func watchReloadSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			if !ready.Load() {
+				log.Warn("Ignoring SIGHUP while providers are initializing")
+				continue
+			}
+			if err := reloadProviders(); err != nil {
+				log.WithError(err).Error("Failed to reload providers")
+			}
+		}
+	}()
+}
+
+// validateConfig aggregates every configuration problem into one error whose
+// message lists them all and whose "problems" context holds them as a list
+func validateConfig(c *config.Config) *errors.Error {
+	errs := c.ValidateAll()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	problems := make([]string, len(errs))
+	for i, err := range errs {
+		problems[i] = err.Error()
+	}
+
+	return errors.ConfigurationErrorf("invalid configuration: %s", strings.Join(problems, "; ")).
+		WithContext("problems", problems)
+}
+
+// reloadProviders re-reads the configuration and rebuilds the registry.
+// Providers that are unchanged keep their circuit-breaker state.
+func reloadProviders() error {
+	newCfg, err := config.Load()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfiguration, "failed to load configuration")
+	}
+
+	if err := validateConfig(newCfg); err != nil {
+		return err
+	}
+
+	err = registry.Reload(func(next *providers.Registry) error {
+		return createProviders(next, newCfg.Providers)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(map[string]interface{}{
+		"providers": len(newCfg.Providers),
+		"enabled":   len(registry.GetEnabledProviders()),
+	}).Info("Reloaded providers")
+
+	return nil
+}
+
+// initProviders creates the configured providers and warns about
+// environment variables their tools reference that are not set. Providers
+// may take credentials from config.yaml or PROVIDERS_DSN instead, so missing
+// variables do not stop the server.
+func initProviders() error {
+	if err := createProviders(registry, cfg.Providers); err != nil {
+		return err
+	}
+
+	if err := checkRequiredEnv(); err != nil {
+		log.WithError(err).Warn("Tools reference unset environment variables")
+	}
+	return nil
+}
+
+// initProvidersInBackground runs initProviders without blocking and marks
+// the server ready once it completes. The returned channel is closed then.
+func initProvidersInBackground() <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := initProviders(); err != nil {
+			log.WithError(err).Fatal("Failed to create providers")
+		}
+		ready.Store(true)
+
+		log.WithFields(map[string]interface{}{
+			"providers": len(registry.GetAllProviders()),
+			"enabled":   len(registry.GetEnabledProviders()),
+		}).Info("Providers ready")
+	}()
+
+	return done
+}
+
+// checkRequiredEnv verifies that every enabled provider has its required
+// environment variables set
+func checkRequiredEnv() error {
+	missing := registry.MissingEnv()
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	details := make([]string, 0, len(names))
+	for _, name := range names {
+		details = append(details, fmt.Sprintf("%s: %s", name, strings.Join(missing[name], ", ")))
+	}
+
+	return errors.ConfigurationErrorf("missing required environment variables (%s)", strings.Join(details, "; "))
+}
+
+// buildManual assembles the UTCP manual from all enabled providers
+func buildManual(ctx context.Context) *utcp.Manual {
+	manual, _ := collectManual(ctx)
+	return manual
+}
+
+// collectManual assembles the UTCP manual like buildManual, also returning
+// how many tools the MAX_MANUAL_TOOLS cap dropped
+func collectManual(ctx context.Context) (*utcp.Manual, int) {
 	manual := utcp.NewManual()
+	manual.Capabilities = &utcp.Capabilities{
+		ProviderTypes: registry.FactoryTypes(),
+		AuthTypes:     utcp.AuthTypes(),
+		ManualVersion: manual.Version,
+	}
+
+	// Bound how long slow providers can hold up discovery
+	if cfg.Server.DiscoveryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Server.DiscoveryTimeout)
+		defer cancel()
+	}
+
+	// Get all tools from enabled providers, skipping any that fail to refresh
+	tools, total, err := registry.GetAllToolsWithTotal(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Some providers failed to refresh tools")
+	}
 
-	// Get all tools from enabled providers
-	tools := registry.GetAllTools()
 	for _, tool := range tools {
 		manual.AddTool(tool)
 	}
 
+	// Hide tools that change remote state when running read-only
+	if cfg.Server.ReadOnlyMode {
+		manual = manual.WithoutMutating()
+	}
+
+	return manual, total - len(tools)
+}
+
+// requireReady responds with 503 and returns false until providers have
+// been created
+func requireReady(c *gin.Context) bool {
+	if ready.Load() {
+		return true
+	}
+
+	err := errors.WithStatusCode(errors.New(errors.ErrorTypeProvider, "providers are still initializing"), http.StatusServiceUnavailable)
+	c.Header("Retry-After", notReadyRetryAfter)
+	middleware.RespondError(c, err)
+	return false
+}
+
+// requestLogger returns the logger for a request, tagged with the client's
+// request id when one was sent
+func requestLogger(c *gin.Context) logger.Logger {
+	if requestID := c.GetHeader(middleware.RequestIDHeader); requestID != "" {
+		return log.WithField("request_id", requestID)
+	}
+	return log
+}
+
+// discoveryCacheStatus reports whether discovery will be served from the
+// tool cache: "hit" when every enabled provider is cached and fresh, "miss"
+// when any must refresh and "disabled" when caching is off
+func discoveryCacheStatus() string {
+	if cfg.Server.ToolCacheTTL <= 0 {
+		return "disabled"
+	}
+
+	for _, provider := range registry.GetEnabledProviders() {
+		cached, ok := provider.(*providers.CachedProvider)
+		if !ok || !cached.Fresh() {
+			return "miss"
+		}
+	}
+
+	return "hit"
+}
+
+func handleUTCPDiscovery(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	start := time.Now()
+
+	includeProvider, err := strconv.ParseBool(c.DefaultQuery("include_provider", "true"))
+	if err != nil {
+		err := errors.ValidationErrorf("invalid include_provider value: %s", c.Query("include_provider"))
+		middleware.RespondError(c, err)
+		return
+	}
+
+	includeDeprecated, err := strconv.ParseBool(c.DefaultQuery("include_deprecated", "true"))
+	if err != nil {
+		err := errors.ValidationErrorf("invalid include_deprecated value: %s", c.Query("include_deprecated"))
+		middleware.RespondError(c, err)
+		return
+	}
+
+	readOnly, err := strconv.ParseBool(c.DefaultQuery("read_only", "false"))
+	if err != nil {
+		err := errors.ValidationErrorf("invalid read_only value: %s", c.Query("read_only"))
+		middleware.RespondError(c, err)
+		return
+	}
+
+	pretty := !cfg.Server.JSONCompact
+	if value := c.Query("pretty"); value != "" {
+		pretty, err = strconv.ParseBool(value)
+		if err != nil {
+			err := errors.ValidationErrorf("invalid pretty value: %s", value)
+			middleware.RespondError(c, err)
+			return
+		}
+	}
+
+	limit := cfg.Server.MaxTools
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			err := errors.ValidationErrorf("invalid limit value: %s", value)
+			middleware.RespondError(c, err)
+			return
+		}
+	}
+
+	cacheStatus := discoveryCacheStatus()
+
+	manual, dropped := collectManual(c.Request.Context())
+	if !includeProvider {
+		manual = manual.WithoutToolProviders()
+	}
+	if !includeDeprecated {
+		manual = manual.WithoutDeprecated()
+	}
+	if readOnly {
+		manual = manual.WithoutMutating()
+	}
+
+	// Report the full count, including tools dropped by MAX_MANUAL_TOOLS, so
+	// clients know when the list was truncated
+	c.Header("X-Total-Tools", strconv.Itoa(len(manual.Tools)+dropped))
+	if limit > 0 {
+		manual = manual.Limit(limit)
+	}
+
+	// Refuse an oversized manual before streaming it as compact JSON; the
+	// other encodings are checked against their serialized size instead
+	format := c.DefaultQuery("format", "json")
+	streamed := !pretty && format != "yaml" && format != "toml"
+	if streamed {
+		if err := manual.CheckSize(cfg.Server.MaxManualBytes); err != nil {
+			respondManualTooLarge(c, err)
+			return
+		}
+	}
+
+	// Expose the checksum as an ETag so clients can validate cached copies
+	if checksum, err := manual.Checksum(); err == nil {
+		c.Header("ETag", `"`+checksum+`"`)
+	}
+
+	if audit != nil {
+		err := audit.Log(logger.AuditEntry{
+			ClientIP:  c.ClientIP(),
+			UserAgent: c.GetHeader("User-Agent"),
+			RequestID: c.GetHeader(middleware.RequestIDHeader),
+			Tools:     len(manual.Tools),
+			Filters: logger.AuditFilters{
+				IncludeProvider:   includeProvider,
+				IncludeDeprecated: includeDeprecated,
+				ReadOnly:          readOnly || cfg.Server.ReadOnlyMode,
+				Limit:             limit,
+			},
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to write audit log")
+		}
+	}
+
+	// Log one event covering the whole request once the response is written
+	defer func() {
+		requestLogger(c).WithFields(map[string]interface{}{
+			"duration_ms":        time.Since(start).Milliseconds(),
+			"tools":              len(manual.Tools),
+			"providers":          len(registry.GetEnabledProviders()),
+			"include_provider":   includeProvider,
+			"include_deprecated": includeDeprecated,
+			"read_only":          readOnly || cfg.Server.ReadOnlyMode,
+			"limit":              limit,
+			"format":             format,
+			"pretty":             pretty,
+			"cache":              cacheStatus,
+			"response_bytes":     c.Writer.Size(),
+			"ip":                 c.ClientIP(),
+			"userAgent":          c.GetHeader("User-Agent"),
+		}).Info("Served UTCP discovery")
+	}()
+
+	// Return the UTCP manual in the requested format, defaulting to JSON
+	switch c.Query("format") {
+	case "yaml":
+		renderManual(c, "application/yaml", manual.ToYAML)
+	case "toml":
+		renderManual(c, "application/toml", manual.ToTOML)
+	default:
+		if !streamed {
+			renderManual(c, "application/json; charset=utf-8", func() ([]byte, error) {
+				data, err := manual.ToJSON()
+				return []byte(data), err
+			})
+			return
+		}
+
+		// Stream compact JSON so large manuals are not buffered before writing
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := manual.WriteJSON(c.Writer); err != nil {
+			log.WithError(err).Error("Failed to stream UTCP discovery")
+		}
+	}
+}
+
+// renderManual writes the manual using the given serializer
+func renderManual(c *gin.Context, contentType string, serialize func() ([]byte, error)) {
+	data, err := serialize()
+	if err != nil {
+		wrapped := errors.Wrap(err, errors.ErrorTypeInternal, "failed to serialize manual")
+		log.WithError(wrapped).Error("Failed to serve UTCP discovery")
+		middleware.RespondError(c, wrapped)
+		return
+	}
+
+	if max := cfg.Server.MaxManualBytes; max > 0 && int64(len(data)) > max {
+		respondManualTooLarge(c, utcp.ErrManualTooLarge)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// respondManualTooLarge reports that the manual exceeds MAX_MANUAL_BYTES
+func respondManualTooLarge(c *gin.Context, err error) {
+	wrapped := errors.Wrap(err, errors.ErrorTypeInternal, "manual exceeds MAX_MANUAL_BYTES; request fewer tools with ?limit=").
+		WithContext("max_bytes", cfg.Server.MaxManualBytes)
+	log.WithError(wrapped).Error("Failed to serve UTCP discovery")
+	middleware.RespondError(c, wrapped)
+}
+
+// toolIndexEntry is the lightweight projection of a tool served by /utcp/index
+type toolIndexEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Provider    string   `json:"provider,omitempty"`
+}
+
+// handleUTCPIndex serves tool names and descriptions without schemas so
+// agents can select tools cheaply before fetching them from /utcp/tools/:name
+func handleUTCPIndex(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	manual := buildManual(c.Request.Context())
+
+	index := make([]toolIndexEntry, 0, len(manual.Tools))
+	for _, tool := range manual.Tools {
+		index = append(index, newToolIndexEntry(tool))
+	}
+
+	c.JSON(http.StatusOK, index)
+}
+
+// newToolIndexEntry projects a tool to its index entry, taking the provider
+// from its provider:<name> tag
+func newToolIndexEntry(tool utcp.Tool) toolIndexEntry {
+	entry := toolIndexEntry{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Tags:        tool.Tags,
+	}
+	for _, tag := range tool.Tags {
+		if strings.HasPrefix(tag, providers.ProviderTagPrefix) {
+			entry.Provider = strings.TrimPrefix(tag, providers.ProviderTagPrefix)
+			break
+		}
+	}
+	return entry
+}
+
+// defaultSearchLimit is how many matches /utcp/search returns without ?limit=
+const defaultSearchLimit = 10
+
+// toolSearchEntry is an index entry with its relevance to a search query
+type toolSearchEntry struct {
+	toolIndexEntry
+	Score float64 `json:"score"`
+}
+
+// handleUTCPSearch ranks tools against the free-text query in ?q= and
+// returns the best matches as index entries with scores
+func handleUTCPSearch(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		err := errors.ValidationError("missing q parameter")
+		middleware.RespondError(c, err)
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			err := errors.ValidationErrorf("invalid limit value: %s", value)
+			middleware.RespondError(c, err)
+			return
+		}
+	}
+
+	results := buildManual(c.Request.Context()).Search(query, limit)
+
+	entries := make([]toolSearchEntry, 0, len(results))
+	for _, result := range results {
+		entries = append(entries, toolSearchEntry{
+			toolIndexEntry: newToolIndexEntry(result.Tool),
+			Score:          result.Score,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   query,
+		"results": entries,
+	})
+}
+
+// handleUTCPTool serves the full definition of a single tool by name
+func handleUTCPTool(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	name := c.Param("name")
+	for _, tool := range buildManual(c.Request.Context()).Tools {
+		if tool.Name == name {
+			c.Header("ETag", `"`+tool.Fingerprint()+`"`)
+			c.JSON(http.StatusOK, tool)
+			return
+		}
+	}
+
+	err := errors.NotFoundError("tool " + name)
+	middleware.RespondError(c, err)
+}
+
+// handleUTCPSchema serves a tool's inputs as a plain JSON Schema object for
+// agent frameworks that take JSON Schema for function calling
+func handleUTCPSchema(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	name := c.Param("name")
+	for _, tool := range buildManual(c.Request.Context()).Tools {
+		if tool.Name == name {
+			c.JSON(http.StatusOK, utcp.SchemaToJSONSchema(tool.Inputs))
+			return
+		}
+	}
+
+	err := errors.NotFoundError("tool " + name)
+	middleware.RespondError(c, err)
+}
+
+func handleUTCPChecksum(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	manual := buildManual(c.Request.Context())
+
+	checksum, err := manual.Checksum()
+	if err != nil {
+		wrapped := errors.Wrap(err, errors.ErrorTypeInternal, "failed to compute manual checksum")
+		log.WithError(wrapped).Error("Failed to serve UTCP checksum")
+		middleware.RespondError(c, wrapped)
+		return
+	}
+
+	c.Header("ETag", `"`+checksum+`"`)
+	c.JSON(http.StatusOK, gin.H{
+		"algorithm": "sha256",
+		"checksum":  checksum,
+		"tools":     len(manual.Tools),
+	})
+}
+
+// providerHealth is the health of a single provider. Unhealthy providers
+// carry the status code and type derived from their health check error.
+type providerHealth struct {
+	Status     string           `json:"status"`
+	StatusCode int              `json:"status_code,omitempty"`
+	ErrorType  errors.ErrorType `json:"error_type,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Breaker    circuit.State    `json:"breaker,omitempty"`
+}
+
+// providerStatuses returns the health of each enabled provider. Providers
+// are checked concurrently, each bounded by its own health_timeout.
+func providerStatuses(ctx context.Context) map[string]providerHealth {
+	enabled := registry.GetEnabledProviders()
+	providerStatus := make(map[string]providerHealth, len(enabled))
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, provider := range enabled {
+		wg.Add(1)
+		go func(provider providers.Provider) {
+			defer wg.Done()
+			health := providerHealthOf(ctx, provider)
+
+			mu.Lock()
+			providerStatus[provider.GetName()] = health
+			mu.Unlock()
+		}(provider)
+	}
+	wg.Wait()
+
+	return providerStatus
+}
+
+// providerHealthOf checks a single provider, consulting its breakers first
+func providerHealthOf(ctx context.Context, provider providers.Provider) providerHealth {
+	name := provider.GetName()
+	health := providerHealth{Status: "healthy"}
+
+	if breaker, ok := registry.Breaker(name); ok && breaker.State() == providers.BreakerOpen {
+		health = providerHealth{Status: "unhealthy (circuit open)", StatusCode: http.StatusServiceUnavailable}
+	}
+
+	checker, ok := provider.(providers.HealthChecker)
+	if !ok {
+		return health
+	}
+
+	breaker, _ := registry.HealthBreaker(name)
+	if breaker != nil && !breaker.Allow() {
+		// Skip probing an upstream that keeps failing until the cooldown ends
+		health = providerHealth{
+			Status:     "unhealthy (circuit open)",
+			StatusCode: http.StatusServiceUnavailable,
+			Error:      "circuit open",
+		}
+	} else if err := checkWithTimeout(ctx, checker, name); err != nil {
+		if breaker != nil {
+			breaker.RecordFailure()
+		}
+		status := "unhealthy"
+		if errors.Is(err, errors.ErrorTypeTimeout) {
+			status = "unhealthy (timeout)"
+		}
+		health = providerHealth{
+			Status:     status,
+			StatusCode: errors.GetStatusCode(err),
+			ErrorType:  errors.GetType(err),
+			Error:      err.Error(),
+		}
+	} else if breaker != nil {
+		breaker.RecordSuccess()
+	}
+
+	if breaker != nil {
+		health.Breaker = breaker.State()
+	}
+	return health
+}
+
+// checkWithTimeout runs checker's health check bounded by the provider's
+// health_timeout. A check still running at the deadline is abandoned and
+// reported as a TimeoutError, even if it ignores context cancellation.
+func checkWithTimeout(ctx context.Context, checker providers.HealthChecker, name string) error {
+	timeout := registry.HealthTimeout(name)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.HealthCheck(checkCtx)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || checkCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+			return err
+		}
+	case <-checkCtx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	err := errors.TimeoutError("health check for provider "+name).
+		WithContext("provider", name).
+		WithContext("timeout", timeout.String())
+	return errors.WithStatusCode(err, http.StatusGatewayTimeout)
+}
+
+func handleOpenAPI(c *gin.Context) {
+	if !requireReady(c) {
+		return
+	}
+
+	doc, skipped := openapi.FromManual(buildManual(c.Request.Context()))
+	for _, tool := range skipped {
+		log.WithField("tool", tool.Name).Warnf("Tool left out of the OpenAPI document: %s", tool.Reason)
+	}
+
+	c.JSON(http.StatusOK, doc)
+}
+
+// handleConfigSchema serves the JSON Schema for config.yaml
+func handleConfigSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", config.JSONSchema())
+}
+
+// levelSetter is implemented by loggers whose level can change at runtime
+type levelSetter interface {
+	SetLevel(level logger.LogLevel)
+}
+
+// logLevelRequest is the body accepted by POST /admin/loglevel
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// handleSetLogLevel changes the server log level without a restart, e.g.
+// to debug a production issue. Request loggers pick up the new level on
+// their next request.
+func handleSetLogLevel(c *gin.Context) {
+	var body logLevelRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		err := errors.ValidationErrorf("invalid request body: %v", err)
+		middleware.RespondError(c, err)
+		return
+	}
+
+	level, err := logger.ParseLevel(body.Level)
+	if err != nil {
+		err := errors.ValidationErrorf("invalid log level: %s", body.Level)
+		middleware.RespondError(c, err)
+		return
+	}
+
+	setter, ok := log.(levelSetter)
+	if !ok {
+		err := errors.InternalErrorf("logger does not support changing level")
+		middleware.RespondError(c, err)
+		return
+	}
+	setter.SetLevel(level)
+
 	log.WithFields(map[string]interface{}{
-		"tools":     len(tools),
-		"providers": len(registry.GetEnabledProviders()),
-		"ip":        c.ClientIP(),
-		"userAgent": c.GetHeader("User-Agent"),
-	}).Info("Serving UTCP discovery")
+		"level": level.String(),
+		"ip":    c.ClientIP(),
+	}).Info("Log level changed via admin endpoint")
 
-	// Return the UTCP manual
-	c.JSON(http.StatusOK, manual)
+	c.JSON(http.StatusOK, gin.H{"level": level.String()})
 }
 
-func handleHealth(c *gin.Context) {
-	enabledProviders := registry.GetEnabledProviders()
-	providerStatus := make(map[string]string)
+// handleSetProviderEnabled returns a handler that enables or disables the
+// named provider
+func handleSetProviderEnabled(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+
+		if err := registry.SetProviderEnabled(name, enabled); err != nil {
+			middleware.RespondError(c, errors.WithProvider(err, name))
+			return
+		}
+
+		log.WithFields(map[string]interface{}{
+			"provider": name,
+			"enabled":  enabled,
+			"ip":       c.ClientIP(),
+		}).Info("Provider toggled via admin endpoint")
 
-	for _, provider := range enabledProviders {
-		providerStatus[provider.GetName()] = "healthy"
+		c.JSON(http.StatusOK, gin.H{
+			"provider": name,
+			"enabled":  enabled,
+		})
 	}
+}
+
+func handleHealth(c *gin.Context) {
+	enabledProviders := registry.GetEnabledProviders()
+	providerStatus := providerStatuses(c.Request.Context())
 
 	health := gin.H{
 		"status": "ok",
 		"providers": gin.H{
-			"total":   len(cfg.Providers),
+			"total":   len(registry.GetAllProviders()),
 			"enabled": len(enabledProviders),
 			"status":  providerStatus,
 		},
 		"server": gin.H{
 			"environment": cfg.Server.Environment,
-			"version":     "0.1.0",
+			"version":     version,
+			"uptime":      time.Since(startTime).Round(time.Second).String(),
 		},
 	}
 
+	// Cumulative since start; pair with uptime to judge recency
+	if counter, ok := log.(*logger.StructuredLogger); ok {
+		counts := counter.Counts()
+		health["logs"] = gin.H{
+			"errors_logged":   counts[logger.ErrorLevel] + counts[logger.FatalLevel],
+			"warnings_logged": counts[logger.WarnLevel],
+		}
+	}
+
 	c.JSON(http.StatusOK, health)
 }
 
-// ginLogger creates a Gin middleware for logging
-func ginLogger() gin.HandlerFunc {
+// handleVersion reports which build is running
+func handleVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version":    version,
+		"git_commit": gitCommit,
+		"build_date": buildDate,
+		"go_version": runtime.Version(),
+	})
+}
+
+// handleLiveness reports that the process is up without checking providers
+func handleLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// handleReadiness reports whether at least one provider can serve tools
+func handleReadiness(c *gin.Context) {
+	if !ready.Load() {
+		c.Header("Retry-After", notReadyRetryAfter)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "initializing"})
+		return
+	}
+
+	healthy := 0
+	for _, health := range providerStatuses(c.Request.Context()) {
+		if health.Status == "healthy" {
+			healthy++
+		}
+	}
+
+	if healthy == 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "unavailable",
+			"healthy": healthy,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"healthy": healthy,
+	})
+}
+
+// ginLogger creates a Gin middleware that writes an access log line per
+// request to accessLog
+func ginLogger(accessLog logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Process request
 		c.Next()
@@ -222,9 +1125,9 @@ func ginLogger() gin.HandlerFunc {
 		}
 
 		if c.Writer.Status() >= 400 {
-			log.WithFields(fields).Error("Request failed")
+			accessLog.WithFields(fields).Error("Request failed")
 		} else {
-			log.WithFields(fields).Info("Request completed")
+			accessLog.WithFields(fields).Info("Request completed")
 		}
 	}
 }
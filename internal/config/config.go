@@ -1,10 +1,15 @@
 package config
 
 import (
+	stderrors "errors"
 	"fmt"
+	"io/fs"
+	"net"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -16,9 +21,28 @@ type Config struct {
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
-	Port        string
-	Environment string
-	LogLevel    string
+	Host                   string
+	Port                   string
+	Environment            string
+	LogLevel               string
+	LogSampleRate          int
+	LogFormat              string
+	RateLimitRPS           float64
+	RateLimitBurst         int
+	MaxConcurrentRefreshes int
+	ProviderCreateAttempts int
+	RequestTimeout         time.Duration
+	DiscoveryTimeout       time.Duration
+	ToolCacheTTL           time.Duration
+	MaxTools               int
+	MaxManualTools         int
+	MaxManualBytes         int64
+	JSONCompact            bool
+	ReadOnlyMode           bool
+	CORSAllowedOrigins     []string
+	AdminToken             string
+	AuditLogPath           string
+	AccessLogPath          string
 }
 
 // ProviderConfig holds configuration for a single provider
@@ -26,8 +50,9 @@ type ProviderConfig struct {
 	Name    string
 	Type    string
 	Enabled bool
-	BaseURL string
+	BaseURL string `mapstructure:"base_url"`
 	Auth    AuthConfig
+	Options map[string]interface{}
 }
 
 // AuthConfig holds authentication configuration
@@ -35,11 +60,11 @@ type AuthConfig struct {
 	Type         string
 	Username     string
 	Password     string
-	APIKey       string
+	APIKey       string `mapstructure:"api_key"`
 	Token        string
-	ClientID     string
-	ClientSecret string
-	TokenURL     string
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	TokenURL     string `mapstructure:"token_url"`
 }
 
 // Load loads configuration from environment and config files
@@ -47,9 +72,24 @@ func Load() (*Config, error) {
 	v := viper.New()
 
 	// Set defaults
+	v.SetDefault("server.host", "")
 	v.SetDefault("server.port", "8080")
 	v.SetDefault("server.environment", "development")
 	v.SetDefault("server.loglevel", "info")
+	v.SetDefault("server.logsamplerate", 1)
+	v.SetDefault("server.logformat", "text")
+	v.SetDefault("server.ratelimitrps", 0)
+	v.SetDefault("server.ratelimitburst", 10)
+	v.SetDefault("server.maxconcurrentrefreshes", 4)
+	v.SetDefault("server.providercreateattempts", 3)
+	v.SetDefault("server.requesttimeout", "10s")
+	v.SetDefault("server.discoverytimeout", "8s")
+	v.SetDefault("server.toolcachettl", "0s")
+	v.SetDefault("server.maxtools", 0)
+	v.SetDefault("server.maxmanualtools", 10000)
+	v.SetDefault("server.maxmanualbytes", 64<<20)
+	v.SetDefault("server.jsoncompact", false)
+	v.SetDefault("server.readonlymode", false)
 
 	// Set config file
 	v.SetConfigName("config")
@@ -59,24 +99,59 @@ func Load() (*Config, error) {
 	v.AddConfigPath("/etc/rh-utcp/")
 
 	// Read config file if exists
-	if err := v.ReadInConfig(); err != nil {
-		// It's ok if config file doesn't exist
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("error reading config file: %w", err)
-		}
+	if err := readConfigWithRetry(v); err != nil {
+		return nil, err
+	}
+
+	// Overlay the profile selected by APP_ENV
+	if err := applyProfile(v, os.Getenv("APP_ENV")); err != nil {
+		return nil, err
 	}
 
 	// Enable environment variables
 	v.SetEnvPrefix("RHUTCP")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
+	v.BindEnv("server.logsamplerate", "LOG_SAMPLE_RATE")
+	v.BindEnv("server.logformat", "LOG_FORMAT")
+	v.BindEnv("server.ratelimitrps", "RATE_LIMIT_RPS")
+	v.BindEnv("server.ratelimitburst", "RATE_LIMIT_BURST")
+	v.BindEnv("server.maxconcurrentrefreshes", "PROVIDER_REFRESH_CONCURRENCY")
+	v.BindEnv("server.providercreateattempts", "PROVIDER_CREATE_ATTEMPTS")
+	v.BindEnv("server.requesttimeout", "REQUEST_TIMEOUT")
+	v.BindEnv("server.discoverytimeout", "DISCOVERY_TIMEOUT")
+	v.BindEnv("server.toolcachettl", "TOOL_CACHE_TTL")
+	v.BindEnv("server.maxtools", "MAX_TOOLS")
+	v.BindEnv("server.maxmanualtools", "MAX_MANUAL_TOOLS")
+	v.BindEnv("server.maxmanualbytes", "MAX_MANUAL_BYTES")
+	v.BindEnv("server.jsoncompact", "JSON_COMPACT")
+	v.BindEnv("server.readonlymode", "READ_ONLY_MODE")
 
 	// Build configuration from environment
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:        getEnvOrDefault("PORT", v.GetString("server.port")),
-			Environment: v.GetString("server.environment"),
-			LogLevel:    v.GetString("server.loglevel"),
+			Host:                   getEnvOrDefault("HOST", os.ExpandEnv(v.GetString("server.host"))),
+			Port:                   getEnvOrDefault("PORT", os.ExpandEnv(v.GetString("server.port"))),
+			Environment:            os.ExpandEnv(v.GetString("server.environment")),
+			LogLevel:               os.ExpandEnv(v.GetString("server.loglevel")),
+			LogSampleRate:          v.GetInt("server.logsamplerate"),
+			LogFormat:              os.ExpandEnv(v.GetString("server.logformat")),
+			RateLimitRPS:           v.GetFloat64("server.ratelimitrps"),
+			RateLimitBurst:         v.GetInt("server.ratelimitburst"),
+			MaxConcurrentRefreshes: v.GetInt("server.maxconcurrentrefreshes"),
+			ProviderCreateAttempts: v.GetInt("server.providercreateattempts"),
+			RequestTimeout:         v.GetDuration("server.requesttimeout"),
+			DiscoveryTimeout:       v.GetDuration("server.discoverytimeout"),
+			ToolCacheTTL:           v.GetDuration("server.toolcachettl"),
+			MaxTools:               v.GetInt("server.maxtools"),
+			MaxManualTools:         v.GetInt("server.maxmanualtools"),
+			MaxManualBytes:         v.GetInt64("server.maxmanualbytes"),
+			JSONCompact:            v.GetBool("server.jsoncompact"),
+			ReadOnlyMode:           v.GetBool("server.readonlymode"),
+			AdminToken:             os.Getenv("ADMIN_TOKEN"),
+			AuditLogPath:           os.Getenv("AUDIT_LOG_PATH"),
+			AccessLogPath:          os.Getenv("ACCESS_LOG_PATH"),
+			CORSAllowedOrigins:     splitList(os.Getenv("CORS_ALLOWED_ORIGINS")),
 		},
 		Providers: []ProviderConfig{},
 	}
@@ -124,6 +199,47 @@ func Load() (*Config, error) {
 		})
 	}
 
+	// Load TestRail provider if configured
+	if testrailURL := os.Getenv("TESTRAIL_BASE_URL"); testrailURL != "" {
+		cfg.Providers = append(cfg.Providers, ProviderConfig{
+			Name:    "testrail",
+			Type:    "testrail",
+			Enabled: true,
+			BaseURL: testrailURL,
+			Auth: AuthConfig{
+				Type:     "basic",
+				Username: os.Getenv("TESTRAIL_USERNAME"),
+				Password: os.Getenv("TESTRAIL_PASSWORD"),
+			},
+		})
+	}
+
+	// Load Kubernetes provider if configured
+	if kubeURL := os.Getenv("KUBE_API_SERVER"); kubeURL != "" {
+		cfg.Providers = append(cfg.Providers, ProviderConfig{
+			Name:    "kubernetes",
+			Type:    "kubernetes",
+			Enabled: true,
+			BaseURL: kubeURL,
+			Auth: AuthConfig{
+				Type:  "personal_token",
+				Token: os.Getenv("KUBE_TOKEN"),
+			},
+		})
+	}
+
+	// Load providers from PROVIDERS_DSN, skipping names already configured
+	for _, dsn := range splitList(os.Getenv("PROVIDERS_DSN")) {
+		provider, err := ParseProviderDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing PROVIDERS_DSN: %w", err)
+		}
+
+		if _, exists := cfg.GetProvider(provider.Name); !exists {
+			cfg.Providers = append(cfg.Providers, provider)
+		}
+	}
+
 	// Load providers from config file if any
 	if v.IsSet("providers") {
 		var fileProviders []ProviderConfig
@@ -133,6 +249,8 @@ func Load() (*Config, error) {
 
 		// Merge with environment-based providers
 		for _, fp := range fileProviders {
+			fp.expandEnv()
+
 			// Skip if already loaded from environment
 			exists := false
 			for _, ep := range cfg.Providers {
@@ -150,21 +268,107 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
-// Validate validates the configuration
+// expandEnv replaces ${VAR} and $VAR references in a provider loaded from the
+// config file. Unset variables expand to empty strings. Only top-level
+// string options are expanded so nested templates such as REST tool paths
+// are left alone.
+func (p *ProviderConfig) expandEnv() {
+	p.Name = os.ExpandEnv(p.Name)
+	p.Type = os.ExpandEnv(p.Type)
+	p.BaseURL = os.ExpandEnv(p.BaseURL)
+
+	p.Auth.Type = os.ExpandEnv(p.Auth.Type)
+	p.Auth.Username = os.ExpandEnv(p.Auth.Username)
+	p.Auth.Password = os.ExpandEnv(p.Auth.Password)
+	p.Auth.APIKey = os.ExpandEnv(p.Auth.APIKey)
+	p.Auth.Token = os.ExpandEnv(p.Auth.Token)
+	p.Auth.ClientID = os.ExpandEnv(p.Auth.ClientID)
+	p.Auth.ClientSecret = os.ExpandEnv(p.Auth.ClientSecret)
+	p.Auth.TokenURL = os.ExpandEnv(p.Auth.TokenURL)
+
+	for key, value := range p.Options {
+		if s, ok := value.(string); ok {
+			p.Options[key] = os.ExpandEnv(s)
+		}
+	}
+}
+
+// Validate validates the configuration, returning the first problem found.
+// Use ValidateAll to report every problem at once.
 func (c *Config) Validate() error {
+	if errs := c.ValidateAll(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll validates the server settings and every provider, returning
+// all problems found in order so they can be fixed in one pass. Each provider
+// contributes at most its first problem.
+func (c *Config) ValidateAll() []error {
+	var errs []error
+
 	// Validate server config
 	if c.Server.Port == "" {
-		return fmt.Errorf("server port is required")
+		errs = append(errs, fmt.Errorf("server port is required"))
+	}
+
+	if c.Server.Host != "" && !validHost(c.Server.Host) {
+		errs = append(errs, fmt.Errorf("server host %q must be an IP address or hostname", c.Server.Host))
+	}
+
+	if c.Server.LogSampleRate < 0 {
+		errs = append(errs, fmt.Errorf("log sample rate must not be negative"))
+	}
+
+	if c.Server.LogFormat != "" && c.Server.LogFormat != "text" && c.Server.LogFormat != "logfmt" {
+		errs = append(errs, fmt.Errorf("log format %q must be text or logfmt", c.Server.LogFormat))
+	}
+
+	if c.Server.RateLimitRPS < 0 {
+		errs = append(errs, fmt.Errorf("rate limit rps must not be negative"))
+	}
+
+	if c.Server.RateLimitRPS > 0 && c.Server.RateLimitBurst < 1 {
+		errs = append(errs, fmt.Errorf("rate limit burst must be at least 1"))
+	}
+
+	if c.Server.ProviderCreateAttempts < 0 {
+		errs = append(errs, fmt.Errorf("provider create attempts must not be negative"))
+	}
+
+	if c.Server.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("request timeout must not be negative"))
+	}
+
+	if c.Server.DiscoveryTimeout < 0 {
+		errs = append(errs, fmt.Errorf("discovery timeout must not be negative"))
+	}
+
+	if c.Server.ToolCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("tool cache ttl must not be negative"))
+	}
+
+	if c.Server.MaxTools < 0 {
+		errs = append(errs, fmt.Errorf("max tools must not be negative"))
+	}
+
+	if c.Server.MaxManualTools < 0 {
+		errs = append(errs, fmt.Errorf("max manual tools must not be negative"))
+	}
+
+	if c.Server.MaxManualBytes < 0 {
+		errs = append(errs, fmt.Errorf("max manual bytes must not be negative"))
 	}
 
 	// Validate providers
 	for _, p := range c.Providers {
 		if err := p.Validate(); err != nil {
-			return fmt.Errorf("provider %s: %w", p.Name, err)
+			errs = append(errs, fmt.Errorf("provider %s: %w", p.Name, err))
 		}
 	}
 
-	return nil
+	return errs
 }
 
 // Validate validates a provider configuration
@@ -216,6 +420,154 @@ func (c *Config) GetProvider(name string) (*ProviderConfig, bool) {
 	return nil, false
 }
 
+// applyProfile overlays the named entry of the config file's profiles map on
+// top of the base settings. Server settings are merged key by key; profile
+// providers replace matching fields of the base provider with the same name
+// and are appended when no base provider matches.
+func applyProfile(v *viper.Viper, profile string) error {
+	if profile == "" {
+		return nil
+	}
+
+	key := "profiles." + strings.ToLower(profile)
+	if !v.IsSet(key) {
+		return nil
+	}
+
+	overlay := make(map[string]interface{})
+
+	if server := v.GetStringMap(key + ".server"); len(server) > 0 {
+		overlay["server"] = server
+	}
+
+	if profileProviders, ok := v.Get(key + ".providers").([]interface{}); ok {
+		baseProviders, _ := v.Get("providers").([]interface{})
+		overlay["providers"] = mergeProviders(baseProviders, profileProviders)
+	}
+
+	if err := v.MergeConfigMap(overlay); err != nil {
+		return errors.Wrapf(err, errors.ErrorTypeConfiguration, "error applying profile %s", profile)
+	}
+
+	return nil
+}
+
+// mergeProviders overlays profile provider entries onto base entries by name
+func mergeProviders(base, profile []interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(base)+len(profile))
+	index := make(map[string]int)
+
+	for _, entry := range base {
+		provider, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		copied := make(map[string]interface{}, len(provider))
+		for k, v := range provider {
+			copied[k] = v
+		}
+
+		if name, _ := copied["name"].(string); name != "" {
+			index[name] = len(merged)
+		}
+		merged = append(merged, copied)
+	}
+
+	for _, entry := range profile {
+		provider, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := provider["name"].(string)
+		if i, exists := index[name]; exists && name != "" {
+			target := merged[i].(map[string]interface{})
+			for k, v := range provider {
+				target[k] = v
+			}
+			continue
+		}
+
+		merged = append(merged, provider)
+	}
+
+	return merged
+}
+
+// Config file reads are retried to ride out transient I/O errors, e.g. on
+// NFS-mounted configuration
+var (
+	readConfigAttempts = 3
+	readConfigBackoff  = 100 * time.Millisecond
+	readInConfig       = (*viper.Viper).ReadInConfig
+)
+
+// readConfigWithRetry reads the config file, retrying transient I/O errors
+// with exponential backoff. A missing config file is not an error.
+func readConfigWithRetry(v *viper.Viper) error {
+	backoff := readConfigBackoff
+
+	var err error
+	for attempt := 1; attempt <= readConfigAttempts; attempt++ {
+		err = readInConfig(v)
+		if err == nil {
+			return nil
+		}
+
+		// It's ok if config file doesn't exist
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return nil
+		}
+
+		// Only I/O errors are worth retrying; parse errors won't go away
+		var pathErr *fs.PathError
+		if !stderrors.As(err, &pathErr) || attempt == readConfigAttempts {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return errors.Wrap(err, errors.ErrorTypeConfiguration, "error reading config file")
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validHost reports whether host is an IP address or a DNS hostname
+func validHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	if len(host) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // getEnvOrDefault returns environment variable or default value
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"io/fs"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 func TestLoad(t *testing.T) {
@@ -33,6 +40,7 @@ func TestLoad(t *testing.T) {
 		os.Unsetenv("JIRA_BASE_URL")
 		os.Unsetenv("WIKI_BASE_URL")
 		os.Unsetenv("GITLAB_BASE_URL")
+		os.Unsetenv("TESTRAIL_BASE_URL")
 		os.Unsetenv("PORT")
 
 		cfg, err := Load()
@@ -53,9 +61,249 @@ func TestLoad(t *testing.T) {
 			t.Errorf("Expected default log level 'info', got %s", cfg.Server.LogLevel)
 		}
 
+		if cfg.Server.Host != "" {
+			t.Errorf("Expected all interfaces by default, got host '%s'", cfg.Server.Host)
+		}
+
 		if len(cfg.Providers) != 0 {
 			t.Errorf("Expected no providers, got %d", len(cfg.Providers))
 		}
+
+		if cfg.Server.RateLimitRPS != 0 {
+			t.Errorf("Expected rate limiting disabled by default, got %v rps", cfg.Server.RateLimitRPS)
+		}
+
+		if cfg.Server.RequestTimeout != 10*time.Second {
+			t.Errorf("Expected default request timeout 10s, got %v", cfg.Server.RequestTimeout)
+		}
+
+		if cfg.Server.DiscoveryTimeout != 8*time.Second {
+			t.Errorf("Expected default discovery timeout 8s, got %v", cfg.Server.DiscoveryTimeout)
+		}
+
+		if cfg.Server.ToolCacheTTL != 0 {
+			t.Errorf("Expected tool caching disabled by default, got %v", cfg.Server.ToolCacheTTL)
+		}
+
+		if cfg.Server.MaxTools != 0 {
+			t.Errorf("Expected no tool limit by default, got %d", cfg.Server.MaxTools)
+		}
+
+		if cfg.Server.LogFormat != "text" {
+			t.Errorf("Expected text log format by default, got %s", cfg.Server.LogFormat)
+		}
+
+		if cfg.Server.ProviderCreateAttempts != 3 {
+			t.Errorf("Expected 3 provider create attempts by default, got %d", cfg.Server.ProviderCreateAttempts)
+		}
+
+		if cfg.Server.MaxManualTools != 10000 {
+			t.Errorf("Expected default max manual tools 10000, got %d", cfg.Server.MaxManualTools)
+		}
+
+		if cfg.Server.MaxManualBytes != 64<<20 {
+			t.Errorf("Expected default max manual bytes %d, got %d", 64<<20, cfg.Server.MaxManualBytes)
+		}
+
+		if cfg.Server.JSONCompact {
+			t.Error("Expected pretty JSON by default")
+		}
+
+		if cfg.Server.ReadOnlyMode {
+			t.Error("Expected read-only mode disabled by default")
+		}
+
+		if len(cfg.Server.CORSAllowedOrigins) != 0 {
+			t.Errorf("Expected CORS disabled by default, got %v", cfg.Server.CORSAllowedOrigins)
+		}
+	})
+
+	t.Run("Load TestRail from environment", func(t *testing.T) {
+		t.Setenv("TESTRAIL_BASE_URL", "https://testrail.test.com")
+		t.Setenv("TESTRAIL_USERNAME", "qa")
+		t.Setenv("TESTRAIL_PASSWORD", "testrail-key")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		testrailProvider, found := cfg.GetProvider("testrail")
+		if !found {
+			t.Fatal("TestRail provider not found")
+		}
+
+		if testrailProvider.Type != "testrail" {
+			t.Errorf("Expected TestRail type 'testrail', got %s", testrailProvider.Type)
+		}
+
+		if testrailProvider.Auth.Type != "basic" || testrailProvider.Auth.Password != "testrail-key" {
+			t.Errorf("Expected basic auth with password 'testrail-key', got %s/%s", testrailProvider.Auth.Type, testrailProvider.Auth.Password)
+		}
+	})
+
+	t.Run("Load log sample rate from environment", func(t *testing.T) {
+		t.Setenv("LOG_SAMPLE_RATE", "10")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.LogSampleRate != 10 {
+			t.Errorf("Expected log sample rate 10, got %d", cfg.Server.LogSampleRate)
+		}
+	})
+
+	t.Run("Load CORS origins from environment", func(t *testing.T) {
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com,")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		origins := cfg.Server.CORSAllowedOrigins
+		if len(origins) != 2 || origins[0] != "https://a.example.com" || origins[1] != "https://b.example.com" {
+			t.Errorf("Expected two CORS origins, got %v", origins)
+		}
+	})
+
+	t.Run("Load tool cache TTL from environment", func(t *testing.T) {
+		t.Setenv("TOOL_CACHE_TTL", "5m")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.ToolCacheTTL != 5*time.Minute {
+			t.Errorf("Expected tool cache TTL 5m, got %v", cfg.Server.ToolCacheTTL)
+		}
+	})
+
+	t.Run("Load max tools from environment", func(t *testing.T) {
+		t.Setenv("MAX_TOOLS", "25")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.MaxTools != 25 {
+			t.Errorf("Expected max tools 25, got %d", cfg.Server.MaxTools)
+		}
+	})
+
+	t.Run("Load manual caps from environment", func(t *testing.T) {
+		t.Setenv("MAX_MANUAL_TOOLS", "500")
+		t.Setenv("MAX_MANUAL_BYTES", "1048576")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.MaxManualTools != 500 {
+			t.Errorf("Expected max manual tools 500, got %d", cfg.Server.MaxManualTools)
+		}
+
+		if cfg.Server.MaxManualBytes != 1048576 {
+			t.Errorf("Expected max manual bytes 1048576, got %d", cfg.Server.MaxManualBytes)
+		}
+	})
+
+	t.Run("Load host from environment", func(t *testing.T) {
+		t.Setenv("HOST", "127.0.0.1")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.Host != "127.0.0.1" {
+			t.Errorf("Expected host '127.0.0.1', got '%s'", cfg.Server.Host)
+		}
+	})
+
+	t.Run("Load JSON compact from environment", func(t *testing.T) {
+		t.Setenv("JSON_COMPACT", "true")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if !cfg.Server.JSONCompact {
+			t.Error("Expected JSON compact to be enabled")
+		}
+	})
+
+	t.Run("Load read-only mode from environment", func(t *testing.T) {
+		t.Setenv("READ_ONLY_MODE", "true")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if !cfg.Server.ReadOnlyMode {
+			t.Error("Expected read-only mode to be enabled")
+		}
+	})
+
+	t.Run("Load log paths from environment", func(t *testing.T) {
+		t.Setenv("AUDIT_LOG_PATH", "/var/log/rh-utcp/audit.log")
+		t.Setenv("ACCESS_LOG_PATH", "/var/log/rh-utcp/access.log")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.AuditLogPath != "/var/log/rh-utcp/audit.log" {
+			t.Errorf("Expected audit log path, got '%s'", cfg.Server.AuditLogPath)
+		}
+
+		if cfg.Server.AccessLogPath != "/var/log/rh-utcp/access.log" {
+			t.Errorf("Expected access log path, got '%s'", cfg.Server.AccessLogPath)
+		}
+	})
+
+	t.Run("Load request timeout from environment", func(t *testing.T) {
+		t.Setenv("REQUEST_TIMEOUT", "5s")
+		t.Setenv("DISCOVERY_TIMEOUT", "2s")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.RequestTimeout != 5*time.Second {
+			t.Errorf("Expected request timeout 5s, got %v", cfg.Server.RequestTimeout)
+		}
+
+		if cfg.Server.DiscoveryTimeout != 2*time.Second {
+			t.Errorf("Expected discovery timeout 2s, got %v", cfg.Server.DiscoveryTimeout)
+		}
+	})
+
+	t.Run("Load rate limit from environment", func(t *testing.T) {
+		t.Setenv("RATE_LIMIT_RPS", "5.5")
+		t.Setenv("RATE_LIMIT_BURST", "20")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.RateLimitRPS != 5.5 {
+			t.Errorf("Expected rate limit rps 5.5, got %v", cfg.Server.RateLimitRPS)
+		}
+
+		if cfg.Server.RateLimitBurst != 20 {
+			t.Errorf("Expected rate limit burst 20, got %d", cfg.Server.RateLimitBurst)
+		}
 	})
 
 	t.Run("Load from environment", func(t *testing.T) {
@@ -183,6 +431,124 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "server port is required",
 		},
+		{
+			name: "Negative rate limit",
+			config: Config{
+				Server: ServerConfig{
+					Port:         "8080",
+					RateLimitRPS: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "rate limit rps must not be negative",
+		},
+		{
+			name: "Negative tool cache TTL",
+			config: Config{
+				Server: ServerConfig{
+					Port:         "8080",
+					ToolCacheTTL: -time.Second,
+				},
+			},
+			wantErr: true,
+			errMsg:  "tool cache ttl must not be negative",
+		},
+		{
+			name: "Valid IPv4 host",
+			config: Config{
+				Server: ServerConfig{
+					Host: "127.0.0.1",
+					Port: "8080",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid IPv6 host",
+			config: Config{
+				Server: ServerConfig{
+					Host: "::1",
+					Port: "8080",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid hostname",
+			config: Config{
+				Server: ServerConfig{
+					Host: "localhost",
+					Port: "8080",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid host",
+			config: Config{
+				Server: ServerConfig{
+					Host: "127.0.0.1:9090",
+					Port: "8080",
+				},
+			},
+			wantErr: true,
+			errMsg:  "must be an IP address or hostname",
+		},
+		{
+			name: "Negative max tools",
+			config: Config{
+				Server: ServerConfig{
+					Port:     "8080",
+					MaxTools: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "max tools must not be negative",
+		},
+		{
+			name: "Unknown log format",
+			config: Config{
+				Server: ServerConfig{
+					Port:      "8080",
+					LogFormat: "json",
+				},
+			},
+			wantErr: true,
+			errMsg:  "log format \"json\" must be text or logfmt",
+		},
+		{
+			name: "Negative provider create attempts",
+			config: Config{
+				Server: ServerConfig{
+					Port:                   "8080",
+					ProviderCreateAttempts: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "provider create attempts must not be negative",
+		},
+		{
+			name: "Negative max manual tools",
+			config: Config{
+				Server: ServerConfig{
+					Port:           "8080",
+					MaxManualTools: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "max manual tools must not be negative",
+		},
+		{
+			name: "Negative max manual bytes",
+			config: Config{
+				Server: ServerConfig{
+					Port:           "8080",
+					MaxManualBytes: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "max manual bytes must not be negative",
+		},
 		{
 			name: "Provider missing name",
 			config: Config{
@@ -345,6 +711,45 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateAll(t *testing.T) {
+	cfg := Config{
+		Server: ServerConfig{
+			Port:     "8080",
+			MaxTools: -1,
+		},
+		Providers: []ProviderConfig{
+			{Name: "jira", Type: "jira", Enabled: true},
+			{Name: "ok", Type: "gitlab", Enabled: true, BaseURL: "https://gitlab.example.com"},
+			{Name: "gitlab", Type: "gitlab", Enabled: true, BaseURL: "https://gitlab.example.com", Auth: AuthConfig{Type: "personal_token"}},
+		},
+	}
+
+	errs := cfg.ValidateAll()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %d: %v", len(errs), errs)
+	}
+
+	expected := []string{
+		"max tools must not be negative",
+		"provider jira: base URL is required",
+		"provider gitlab: token required",
+	}
+	for i, want := range expected {
+		if !contains(errs[i].Error(), want) {
+			t.Errorf("Expected error %d to contain '%s', got '%s'", i, want, errs[i].Error())
+		}
+	}
+
+	if err := cfg.Validate(); err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("Expected Validate to return the first error, got %v", err)
+	}
+
+	valid := Config{Server: ServerConfig{Port: "8080"}}
+	if errs := valid.ValidateAll(); len(errs) != 0 {
+		t.Errorf("Expected no errors for a valid config, got %v", errs)
+	}
+}
+
 func TestGetProvider(t *testing.T) {
 	cfg := &Config{
 		Providers: []ProviderConfig{
@@ -437,3 +842,304 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// chdirTemp switches to a fresh temporary directory for the test
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd failed: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir failed: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return dir
+}
+
+// stubReadInConfig replaces the config reader for the test
+func stubReadInConfig(t *testing.T, read func(v *viper.Viper) error) {
+	t.Helper()
+
+	previousRead, previousBackoff := readInConfig, readConfigBackoff
+	readInConfig = read
+	readConfigBackoff = time.Millisecond
+	t.Cleanup(func() {
+		readInConfig = previousRead
+		readConfigBackoff = previousBackoff
+	})
+}
+
+func TestLoadRetriesTransientReadErrors(t *testing.T) {
+	dir := chdirTemp(t)
+
+	attempts := 0
+	stubReadInConfig(t, func(v *viper.Viper) error {
+		attempts++
+		if attempts == 1 {
+			// The file only becomes readable after the first attempt
+			content := []byte("server:\n  environment: staging\n")
+			if err := os.WriteFile(filepath.Join(dir, "config.yaml"), content, 0o644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+			return &fs.PathError{Op: "read", Path: "config.yaml", Err: syscall.EIO}
+		}
+		return v.ReadInConfig()
+	})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 read attempts, got %d", attempts)
+	}
+
+	if cfg.Server.Environment != "staging" {
+		t.Errorf("Expected environment 'staging' from config file, got %s", cfg.Server.Environment)
+	}
+}
+
+func TestLoadReadErrorAfterRetries(t *testing.T) {
+	chdirTemp(t)
+
+	attempts := 0
+	stubReadInConfig(t, func(v *viper.Viper) error {
+		attempts++
+		return &fs.PathError{Op: "read", Path: "config.yaml", Err: syscall.EIO}
+	})
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Expected error after exhausting retries, got nil")
+	}
+
+	if attempts != readConfigAttempts {
+		t.Errorf("Expected %d read attempts, got %d", readConfigAttempts, attempts)
+	}
+
+	if !errors.Is(err, errors.ErrorTypeConfiguration) {
+		t.Errorf("Expected configuration error, got %v", err)
+	}
+}
+
+func TestLoadDoesNotRetryParseErrors(t *testing.T) {
+	dir := chdirTemp(t)
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("server: [unclosed"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	attempts := 0
+	stubReadInConfig(t, func(v *viper.Viper) error {
+		attempts++
+		return v.ReadInConfig()
+	})
+
+	if _, err := Load(); err == nil {
+		t.Fatal("Expected parse error, got nil")
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected 1 read attempt for a parse error, got %d", attempts)
+	}
+}
+
+func TestLoadMissingConfigFile(t *testing.T) {
+	chdirTemp(t)
+
+	if _, err := Load(); err != nil {
+		t.Errorf("Expected missing config file to be ignored, got %v", err)
+	}
+}
+
+const expandConfig = `
+server:
+  environment: ${DEPLOY_ENV}
+  logformat: ${DEPLOY_LOG_FORMAT}
+providers:
+  - name: tracker
+    type: jira
+    enabled: true
+    base_url: https://${TRACKER_HOST}/jira
+    auth:
+      type: basic
+      username: $TRACKER_USER
+      password: ${TRACKER_PASSWORD}
+    options:
+      tool_prefix: ${TRACKER_PREFIX}
+      cache_path: ${UNSET_CACHE_PATH}
+`
+
+func TestLoadExpandsEnv(t *testing.T) {
+	for _, name := range []string{"JIRA_BASE_URL", "WIKI_BASE_URL", "GITLAB_BASE_URL", "TESTRAIL_BASE_URL", "KUBE_API_SERVER", "PROVIDERS_DSN"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("DEPLOY_ENV", "staging")
+	t.Setenv("DEPLOY_LOG_FORMAT", "logfmt")
+	t.Setenv("TRACKER_HOST", "tracker.example.com")
+	t.Setenv("TRACKER_USER", "bot")
+	t.Setenv("TRACKER_PASSWORD", "s3cret")
+	t.Setenv("TRACKER_PREFIX", "corp_")
+	t.Setenv("UNSET_CACHE_PATH", "")
+	os.Unsetenv("UNSET_CACHE_PATH")
+
+	dir := chdirTemp(t)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(expandConfig), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Environment != "staging" {
+		t.Errorf("Expected environment 'staging', got '%s'", cfg.Server.Environment)
+	}
+
+	if cfg.Server.LogFormat != "logfmt" {
+		t.Errorf("Expected log format 'logfmt', got '%s'", cfg.Server.LogFormat)
+	}
+
+	tracker, found := cfg.GetProvider("tracker")
+	if !found {
+		t.Fatal("Tracker provider not found")
+	}
+
+	if tracker.BaseURL != "https://tracker.example.com/jira" {
+		t.Errorf("Expected expanded base URL, got '%s'", tracker.BaseURL)
+	}
+
+	if tracker.Auth.Username != "bot" || tracker.Auth.Password != "s3cret" {
+		t.Errorf("Expected expanded credentials, got %s/%s", tracker.Auth.Username, tracker.Auth.Password)
+	}
+
+	if tracker.Options["tool_prefix"] != "corp_" {
+		t.Errorf("Expected expanded tool_prefix 'corp_', got %v", tracker.Options["tool_prefix"])
+	}
+
+	if tracker.Options["cache_path"] != "" {
+		t.Errorf("Expected unset variable to expand to empty, got %v", tracker.Options["cache_path"])
+	}
+}
+
+const profileConfig = `
+server:
+  environment: development
+  loglevel: info
+providers:
+  - name: jira
+    type: jira
+    enabled: true
+    base_url: https://jira.dev.example.com
+    auth:
+      type: basic
+      username: dev-user
+      password: dev-pass
+profiles:
+  staging:
+    server:
+      environment: staging
+      loglevel: debug
+    providers:
+      - name: jira
+        base_url: https://jira.staging.example.com
+      - name: wiki
+        type: wiki
+        enabled: true
+        base_url: https://wiki.staging.example.com
+  production:
+    server:
+      environment: production
+`
+
+func TestLoadProfiles(t *testing.T) {
+	for _, name := range []string{"JIRA_BASE_URL", "WIKI_BASE_URL", "GITLAB_BASE_URL", "TESTRAIL_BASE_URL"} {
+		t.Setenv(name, "")
+	}
+
+	dir := chdirTemp(t)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(profileConfig), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	t.Run("Staging profile overlay", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.Environment != "staging" || cfg.Server.LogLevel != "debug" {
+			t.Errorf("Expected staging/debug server settings, got %s/%s", cfg.Server.Environment, cfg.Server.LogLevel)
+		}
+
+		jira, found := cfg.GetProvider("jira")
+		if !found {
+			t.Fatal("Jira provider not found")
+		}
+
+		if jira.BaseURL != "https://jira.staging.example.com" {
+			t.Errorf("Expected staging Jira URL, got %s", jira.BaseURL)
+		}
+
+		if jira.Type != "jira" || jira.Auth.Username != "dev-user" {
+			t.Errorf("Expected base Jira type and auth to be kept, got %s/%s", jira.Type, jira.Auth.Username)
+		}
+
+		if _, found := cfg.GetProvider("wiki"); !found {
+			t.Error("Expected profile-only wiki provider to be added")
+		}
+	})
+
+	t.Run("Production profile overlay", func(t *testing.T) {
+		t.Setenv("APP_ENV", "production")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.Environment != "production" || cfg.Server.LogLevel != "info" {
+			t.Errorf("Expected production/info server settings, got %s/%s", cfg.Server.Environment, cfg.Server.LogLevel)
+		}
+
+		jira, _ := cfg.GetProvider("jira")
+		if jira.BaseURL != "https://jira.dev.example.com" {
+			t.Errorf("Expected base Jira URL, got %s", jira.BaseURL)
+		}
+	})
+
+	t.Run("Environment variables win over profiles", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+		t.Setenv("RHUTCP_SERVER_LOGLEVEL", "warn")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.LogLevel != "warn" {
+			t.Errorf("Expected log level 'warn' from environment, got %s", cfg.Server.LogLevel)
+		}
+	})
+
+	t.Run("No profile selected", func(t *testing.T) {
+		t.Setenv("APP_ENV", "")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if cfg.Server.Environment != "development" {
+			t.Errorf("Expected base environment 'development', got %s", cfg.Server.Environment)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package config
+
+import "encoding/json"
+
+// JSONSchemaDraft is the JSON Schema dialect used by JSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema describing config.yaml so editors can
+// validate configuration files. Auth variants list the fields Validate
+// requires for each auth type.
+func JSONSchema() []byte {
+	schema := map[string]interface{}{
+		"$schema":     JSONSchemaDraft,
+		"title":       "rh-utcp configuration",
+		"type":        "object",
+		"description": "Configuration file for the rh-utcp discovery server",
+		"properties": map[string]interface{}{
+			"server":    map[string]interface{}{"$ref": "#/$defs/server"},
+			"providers": map[string]interface{}{"$ref": "#/$defs/providers"},
+			"profiles": map[string]interface{}{
+				"type":        "object",
+				"description": "Environment profiles selected by APP_ENV",
+				"additionalProperties": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"server":    map[string]interface{}{"$ref": "#/$defs/server"},
+						"providers": map[string]interface{}{"$ref": "#/$defs/providers"},
+					},
+				},
+			},
+		},
+		"$defs": map[string]interface{}{
+			"server":    serverSchema(),
+			"providers": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/provider"}},
+			"provider":  providerSchema(),
+			"auth":      authSchema(),
+		},
+	}
+
+	// Marshalling only fails for unsupported types, which the schema never contains
+	data, _ := json.MarshalIndent(schema, "", "  ")
+	return data
+}
+
+// serverSchema describes the server section
+func serverSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"port":                   map[string]interface{}{"type": []string{"string", "integer"}, "description": "Listen port"},
+			"environment":            map[string]interface{}{"type": "string", "description": "Deployment environment, e.g. production"},
+			"loglevel":               map[string]interface{}{"type": "string", "enum": []string{"debug", "info", "warn", "error"}},
+			"logsamplerate":          map[string]interface{}{"type": "integer", "minimum": 0},
+			"ratelimitrps":           map[string]interface{}{"type": "number", "minimum": 0},
+			"ratelimitburst":         map[string]interface{}{"type": "integer", "minimum": 0},
+			"maxconcurrentrefreshes": map[string]interface{}{"type": "integer", "minimum": 0},
+			"requesttimeout":         durationSchema(),
+			"discoverytimeout":       durationSchema(),
+			"toolcachettl":           durationSchema(),
+			"maxtools":               map[string]interface{}{"type": "integer", "minimum": 0},
+		},
+	}
+}
+
+// durationSchema describes a Go duration string such as "30s"
+func durationSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Duration, e.g. 30s or 5m",
+		"pattern":     `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`,
+	}
+}
+
+// providerSchema describes a single providers entry
+func providerSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"name", "type"},
+		"properties": map[string]interface{}{
+			"name":     map[string]interface{}{"type": "string", "minLength": 1},
+			"type":     map[string]interface{}{"type": "string", "minLength": 1, "description": "Provider type, e.g. jira, wiki, confluence, gitlab, testrail, kubernetes, remote or rest"},
+			"enabled":  map[string]interface{}{"type": "boolean"},
+			"base_url": map[string]interface{}{"type": "string"},
+			"auth":     map[string]interface{}{"$ref": "#/$defs/auth"},
+			"options":  map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+// authRequired lists the fields each auth type requires, mirroring Validate
+var authRequired = map[string][]string{
+	"basic":          {"username", "password"},
+	"api_key":        {"api_key"},
+	"personal_token": {"token"},
+	"oauth2":         {"client_id", "client_secret", "token_url"},
+}
+
+// authSchema describes the auth block with one variant per auth type
+func authSchema() map[string]interface{} {
+	types := []string{"basic", "api_key", "personal_token", "oauth2"}
+
+	variants := make([]interface{}, 0, len(types))
+	for _, authType := range types {
+		variants = append(variants, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"type": map[string]interface{}{"const": authType}},
+				"required":   []string{"type"},
+			},
+			"then": map[string]interface{}{"required": authRequired[authType]},
+		})
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type":          map[string]interface{}{"type": "string", "enum": types},
+			"username":      map[string]interface{}{"type": "string"},
+			"password":      map[string]interface{}{"type": "string"},
+			"api_key":       map[string]interface{}{"type": "string"},
+			"token":         map[string]interface{}{"type": "string"},
+			"client_id":     map[string]interface{}{"type": "string"},
+			"client_secret": map[string]interface{}{"type": "string"},
+			"token_url":     map[string]interface{}{"type": "string"},
+		},
+		"allOf": variants,
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("Expected schema to be valid JSON, got %v", err)
+	}
+
+	if schema["$schema"] != JSONSchemaDraft {
+		t.Errorf("Expected $schema %s, got %v", JSONSchemaDraft, schema["$schema"])
+	}
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected properties object in schema")
+	}
+
+	for _, key := range []string{"server", "providers"} {
+		if _, exists := properties[key]; !exists {
+			t.Errorf("Expected property '%s' in schema", key)
+		}
+	}
+
+	defs, ok := schema["$defs"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected $defs object in schema")
+	}
+
+	for _, key := range []string{"providers", "provider", "auth"} {
+		if _, exists := defs[key]; !exists {
+			t.Errorf("Expected definition '%s' in schema", key)
+		}
+	}
+
+	auth := defs["auth"].(map[string]interface{})
+	variants, ok := auth["allOf"].([]interface{})
+	if !ok || len(variants) != len(authRequired) {
+		t.Fatalf("Expected %d auth variants, got %v", len(authRequired), auth["allOf"])
+	}
+
+	// The oauth2 variant requires all three client fields
+	found := false
+	for _, variant := range variants {
+		v := variant.(map[string]interface{})
+		cond := v["if"].(map[string]interface{})["properties"].(map[string]interface{})["type"].(map[string]interface{})
+		if cond["const"] != "oauth2" {
+			continue
+		}
+		found = true
+
+		required := v["then"].(map[string]interface{})["required"].([]interface{})
+		if len(required) != 3 {
+			t.Errorf("Expected 3 required oauth2 fields, got %v", required)
+		}
+	}
+
+	if !found {
+		t.Error("Expected an oauth2 auth variant")
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+)
+
+// AdminTokenHeader is the header carrying the admin shared secret
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminAuth creates a Gin middleware that requires the X-Admin-Token header
+// to match token. An empty token disables access entirely.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(AdminTokenHeader)
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			RespondError(c, errors.ForbiddenError("invalid admin token"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupAdminRouter(token string) *gin.Engine {
+	r := gin.New()
+	admin := r.Group("/admin", AdminAuth(token))
+	admin.POST("/ping", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return r
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		provided string
+		expected int
+	}{
+		{"Matching token", "secret", "secret", http.StatusOK},
+		{"Wrong token", "secret", "guess", http.StatusForbidden},
+		{"Missing token", "secret", "", http.StatusForbidden},
+		{"Admin disabled", "", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupAdminRouter(tt.token)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/admin/ping", nil)
+			if tt.provided != "" {
+				req.Header.Set(AdminTokenHeader, tt.provided)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupCORSRouter(origins []string) *gin.Engine {
+	r := gin.New()
+	r.Use(CORS(origins))
+	r.GET("/utcp", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return r
+}
+
+func TestCORSPreflight(t *testing.T) {
+	r := setupCORSRouter([]string{"https://agent.example.com"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/utcp", nil)
+	req.Header.Set("Origin", "https://agent.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "https://agent.example.com" {
+		t.Errorf("Expected allowed origin header, got '%s'", origin)
+	}
+
+	if methods := w.Header().Get("Access-Control-Allow-Methods"); methods == "" {
+		t.Error("Expected Access-Control-Allow-Methods header")
+	}
+
+	if headers := w.Header().Get("Access-Control-Allow-Headers"); headers == "" {
+		t.Error("Expected Access-Control-Allow-Headers header")
+	}
+}
+
+func TestCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		origin   string
+		expected string
+	}{
+		{"Allowed origin", []string{"https://agent.example.com"}, "https://agent.example.com", "https://agent.example.com"},
+		{"Disallowed origin", []string{"https://agent.example.com"}, "https://evil.example.com", ""},
+		{"Wildcard", []string{"*"}, "https://any.example.com", "*"},
+		{"No origin header", []string{"*"}, "", ""},
+		{"CORS disabled", nil, "https://agent.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupCORSRouter(tt.allowed)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/utcp", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", w.Code)
+			}
+
+			if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != tt.expected {
+				t.Errorf("Expected Access-Control-Allow-Origin '%s', got '%s'", tt.expected, origin)
+			}
+		})
+	}
+}
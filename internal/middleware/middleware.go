@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+)
+
+// DebugErrorsHeader requests stack traces in error bodies when ErrorDetails
+// allows them
+const DebugErrorsHeader = "X-Debug-Errors"
+
+// debugErrorsKey marks requests whose error bodies may include stacks
+const debugErrorsKey = "middleware.debug_errors"
+
+// ErrorDetails creates a Gin middleware that lets clients request stack
+// traces in error bodies with DebugErrorsHeader. Stacks are never exposed
+// when environment is "production".
+func ErrorDetails(environment string) gin.HandlerFunc {
+	allowed := environment != "production"
+
+	return func(c *gin.Context) {
+		if allowed && c.GetHeader(DebugErrorsHeader) != "" {
+			c.Set(debugErrorsKey, true)
+		}
+		c.Next()
+	}
+}
+
+// RespondError aborts the request with an error body whose status code is
+// derived from the error. The body is plain text when the client prefers
+// text/plain and structured JSON otherwise, with the stack included when
+// ErrorDetails allowed it. Middleware and handlers both report errors
+// through it so every error response is negotiated the same way.
+func RespondError(c *gin.Context, err *errors.Error) {
+	status := errors.GetStatusCode(err)
+
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEPlain) == gin.MIMEPlain {
+		c.Data(status, "text/plain; charset=utf-8", []byte(fmt.Sprintf("%s: %s\n", err.Type, err.Message)))
+		c.Abort()
+		return
+	}
+
+	body := gin.H{
+		"type":    err.Type,
+		"message": err.Message,
+		"context": err.Context,
+	}
+	if c.GetBool(debugErrorsKey) {
+		body["stack"] = err.Stack
+	}
+
+	c.AbortWithStatusJSON(status, gin.H{"error": body})
+}
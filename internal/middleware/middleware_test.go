@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+)
+
+// errorRouter returns a router whose /fail route aborts with a not found error
+func errorRouter(environment string) *gin.Engine {
+	r := gin.New()
+	r.Use(ErrorDetails(environment))
+	r.GET("/fail", func(c *gin.Context) {
+		RespondError(c, errors.NotFoundError("widget"))
+	})
+	return r
+}
+
+func TestRespondErrorNegotiation(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{"No Accept header", "", "application/json"},
+		{"Any type", "*/*", "application/json"},
+		{"JSON", "application/json", "application/json"},
+		{"Plain text", "text/plain", "text/plain"},
+		{"JSON preferred over text", "application/json, text/plain;q=0.5", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/fail", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			errorRouter("development").ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("Expected status 404, got %d", w.Code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, ct)
+			}
+
+			if tt.contentType == "text/plain" {
+				if !strings.HasPrefix(w.Body.String(), "not_found: ") {
+					t.Errorf("Expected plain text error, got %q", w.Body.String())
+				}
+				return
+			}
+
+			var body map[string]map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if _, exists := body["error"]["stack"]; exists {
+				t.Error("Expected no stack without the debug header")
+			}
+		})
+	}
+}
+
+func TestRespondErrorDebugStack(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		wantStack   bool
+	}{
+		{"Development", "development", true},
+		{"Production", "production", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/fail", nil)
+			req.Header.Set(DebugErrorsHeader, "true")
+			errorRouter(tt.environment).ServeHTTP(w, req)
+
+			var body map[string]map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+
+			stack, exists := body["error"]["stack"]
+			if exists != tt.wantStack {
+				t.Errorf("Expected stack present=%v, got %v", tt.wantStack, stack)
+			}
+
+			if tt.wantStack {
+				if frames, ok := stack.([]interface{}); !ok || len(frames) == 0 {
+					t.Errorf("Expected stack frames, got %v", stack)
+				}
+			}
+		})
+	}
+}
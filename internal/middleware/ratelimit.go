@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+)
+
+// shardCount is the number of shards used to spread bucket lock contention
+const shardCount = 16
+
+// bucket is a token bucket for a single client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// shard holds a subset of buckets guarded by its own lock
+type shard struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// RateLimiter is a token-bucket rate limiter keyed by client identifier
+type RateLimiter struct {
+	rps     float64
+	burst   int
+	idleTTL time.Duration
+	shards  [shardCount]*shard
+	now     func() time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewRateLimiter creates a new rate limiter allowing rps requests per second
+// with bursts of up to burst requests per client
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	// A bucket idle for longer than a full refill is indistinguishable from a new one
+	idleTTL := time.Minute
+	if refill := time.Duration(float64(burst) / rps * float64(time.Second)); refill > idleTTL {
+		idleTTL = refill
+	}
+
+	l := &RateLimiter{
+		rps:     rps,
+		burst:   burst,
+		idleTTL: idleTTL,
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+
+	for i := range l.shards {
+		l.shards[i] = &shard{buckets: make(map[string]*bucket)}
+	}
+
+	return l
+}
+
+// Allow reports whether a request for key may proceed. When it may not,
+// the returned duration is how long until a token becomes available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	s := l.shardFor(key)
+	now := l.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(l.burst), lastSeen: now}
+		s.buckets[key] = b
+	}
+
+	// Refill tokens based on elapsed time
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(float64(l.burst), b.tokens+elapsed*l.rps)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+	return false, wait
+}
+
+// Cleanup removes buckets that have been idle longer than the idle TTL
+func (l *RateLimiter) Cleanup() {
+	cutoff := l.now().Add(-l.idleTTL)
+
+	for _, s := range l.shards {
+		s.mu.Lock()
+		for key, b := range s.buckets {
+			if b.lastSeen.Before(cutoff) {
+				delete(s.buckets, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Len returns the number of tracked buckets
+func (l *RateLimiter) Len() int {
+	total := 0
+	for _, s := range l.shards {
+		s.mu.Lock()
+		total += len(s.buckets)
+		s.mu.Unlock()
+	}
+	return total
+}
+
+// StartCleanup periodically removes idle buckets until Stop is called
+func (l *RateLimiter) StartCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.Cleanup()
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic cleanup goroutine
+func (l *RateLimiter) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+}
+
+// shardFor returns the shard responsible for key
+func (l *RateLimiter) shardFor(key string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return l.shards[h.Sum32()%shardCount]
+}
+
+// RateLimit creates a Gin middleware that limits requests per client IP
+func RateLimit(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, wait := limiter.Allow(c.ClientIP())
+		if allowed {
+			c.Next()
+			return
+		}
+
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+
+		err := errors.New(errors.ErrorTypeTimeout, "rate limit exceeded").
+			WithContext("retry_after", retryAfter)
+
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		RespondError(c, errors.WithStatusCode(err, http.StatusTooManyRequests))
+	}
+}
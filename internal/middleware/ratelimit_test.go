@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func setupRateLimitRouter(limiter *RateLimiter) *gin.Engine {
+	r := gin.New()
+	r.Use(RateLimit(limiter))
+	r.GET("/utcp", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"version": "0.1.0"})
+	})
+	return r
+}
+
+func doRequest(r *gin.Engine, ip string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/utcp", nil)
+	req.RemoteAddr = ip + ":12345"
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	limiter := NewRateLimiter(1, 2)
+	r := setupRateLimitRouter(limiter)
+
+	for i := 0; i < 2; i++ {
+		if w := doRequest(r, "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i, w.Code)
+		}
+	}
+
+	w := doRequest(r, "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected Retry-After '1', got '%s'", w.Header().Get("Retry-After"))
+	}
+
+	var body map[string]map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if body["error"]["type"] != "timeout" {
+		t.Errorf("Expected error type 'timeout', got %v", body["error"]["type"])
+	}
+
+	if body["error"]["message"] != "rate limit exceeded" {
+		t.Errorf("Expected message 'rate limit exceeded', got %v", body["error"]["message"])
+	}
+}
+
+func TestRateLimitIndependentIPs(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	r := setupRateLimitRouter(limiter)
+
+	if w := doRequest(r, "10.0.0.1"); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for first IP, got %d", w.Code)
+	}
+
+	if w := doRequest(r, "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 for first IP, got %d", w.Code)
+	}
+
+	if w := doRequest(r, "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for second IP, got %d", w.Code)
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(2, 1)
+	limiter.now = func() time.Time { return now }
+
+	if ok, _ := limiter.Allow("client"); !ok {
+		t.Fatal("Expected first request to be allowed")
+	}
+
+	ok, wait := limiter.Allow("client")
+	if ok {
+		t.Fatal("Expected second request to be denied")
+	}
+
+	if wait != 500*time.Millisecond {
+		t.Errorf("Expected wait of 500ms, got %v", wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.Allow("client"); !ok {
+		t.Error("Expected request to be allowed after refill")
+	}
+}
+
+func TestRateLimiterCleanup(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(10, 5)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("idle")
+	now = now.Add(30 * time.Second)
+	limiter.Allow("active")
+
+	if limiter.Len() != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", limiter.Len())
+	}
+
+	now = now.Add(45 * time.Second)
+	limiter.Cleanup()
+
+	if limiter.Len() != 1 {
+		t.Errorf("Expected 1 bucket after cleanup, got %d", limiter.Len())
+	}
+
+	if ok, _ := limiter.Allow("active"); !ok {
+		t.Error("Expected active bucket to be retained")
+	}
+}
+
+func TestRateLimiterStop(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	limiter.StartCleanup(time.Millisecond)
+
+	// Stop must be safe to call more than once
+	limiter.Stop()
+	limiter.Stop()
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+	"github.com/rh-utcp/rh-utcp/pkg/logger"
+)
+
+// RequestIDHeader is the header carrying the client-supplied request id
+const RequestIDHeader = "X-Request-ID"
+
+// Recovery creates a Gin middleware that recovers from panics, logs them as
+// internal errors with their stack trace and request context, and responds
+// with a 500 error body
+func Recovery(log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			err := errors.InternalErrorf("panic: %v", recovered).
+				WithContext("path", c.Request.URL.Path)
+			if requestID := c.GetHeader(RequestIDHeader); requestID != "" {
+				err.WithContext("request_id", requestID)
+			}
+
+			log.WithFields(map[string]interface{}{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"stack":  "\n" + errors.FormatStack(errors.GetStack(err)),
+			}).Error(err.Error())
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			RespondError(c, err)
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/logger"
+)
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{
+		Level:  "error",
+		Output: &buf,
+	})
+
+	r := gin.New()
+	r.Use(Recovery(log))
+	r.GET("/boom", func(c *gin.Context) {
+		panic("something exploded")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/boom", nil)
+	req.Header.Set(RequestIDHeader, "req-7")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	var body struct {
+		Error struct {
+			Type    string                 `json:"type"`
+			Message string                 `json:"message"`
+			Context map[string]interface{} `json:"context"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if body.Error.Type != "internal" {
+		t.Errorf("Expected error type 'internal', got '%s'", body.Error.Type)
+	}
+	if body.Error.Message != "panic: something exploded" {
+		t.Errorf("Expected panic message, got '%s'", body.Error.Message)
+	}
+	if body.Error.Context["request_id"] != "req-7" || body.Error.Context["path"] != "/boom" {
+		t.Errorf("Expected request_id and path in context, got %v", body.Error.Context)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "[ERROR]") || !strings.Contains(output, "something exploded") {
+		t.Errorf("Expected error log with panic message, got %s", output)
+	}
+	if !strings.Contains(output, "recovery_test.go") {
+		t.Errorf("Expected log to include the panic stack, got %s", output)
+	}
+}
+
+func TestRecoveryNoPanic(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Output: &buf})
+
+	r := gin.New()
+	r.Use(Recovery(log))
+	r.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/ok", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no log output, got %s", buf.String())
+	}
+}
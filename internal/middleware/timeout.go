@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+)
+
+// RequestTimeout creates a Gin middleware that bounds each request with a
+// context deadline. Handlers are expected to honor c.Request.Context(); if
+// the deadline passes before a response is written, a 504 timeout error is
+// returned. Requests whose path starts with one of exemptPrefixes are not
+// bounded.
+func RequestTimeout(timeout time.Duration, exemptPrefixes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || isExempt(c.Request.URL.Path, exemptPrefixes) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
+			err := errors.TimeoutError(c.Request.Method+" "+c.Request.URL.Path).
+				WithContext("timeout", timeout.String())
+			RespondError(c, errors.WithStatusCode(err, http.StatusGatewayTimeout))
+		}
+	}
+}
+
+// isExempt reports whether path starts with any of the given prefixes
+func isExempt(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// slowHandler waits for delay unless the request context ends first
+func slowHandler(delay time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		select {
+		case <-time.After(delay):
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		case <-c.Request.Context().Done():
+		}
+	}
+}
+
+func setupTimeoutRouter(timeout time.Duration) *gin.Engine {
+	r := gin.New()
+	r.Use(RequestTimeout(timeout, "/health"))
+	r.GET("/slow", slowHandler(time.Second))
+	r.GET("/fast", slowHandler(0))
+	r.GET("/health", slowHandler(50*time.Millisecond))
+	return r
+}
+
+func TestRequestTimeoutFires(t *testing.T) {
+	r := setupTimeoutRouter(20 * time.Millisecond)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	r.ServeHTTP(w, req)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected request to be cut short, took %v", elapsed)
+	}
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected status 504, got %d", w.Code)
+	}
+
+	var body map[string]map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if body["error"]["type"] != "timeout" {
+		t.Errorf("Expected error type 'timeout', got %v", body["error"]["type"])
+	}
+}
+
+func TestRequestTimeoutFastHandler(t *testing.T) {
+	r := setupTimeoutRouter(100 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/fast", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestRequestTimeoutExemptPath(t *testing.T) {
+	r := setupTimeoutRouter(10 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected exempt health check to succeed, got %d", w.Code)
+	}
+}
+
+func TestRequestTimeoutDisabled(t *testing.T) {
+	r := gin.New()
+	r.Use(RequestTimeout(0))
+	r.GET("/slow", slowHandler(20*time.Millisecond))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with timeout disabled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/pkg/circuit"
+)
+
+// BreakerState describes the state of a provider's circuit breaker
+type BreakerState = circuit.State
+
+const (
+	// BreakerClosed allows refreshes through
+	BreakerClosed = circuit.Closed
+	// BreakerOpen rejects refreshes until the cooldown elapses
+	BreakerOpen = circuit.Open
+	// BreakerHalfOpen allows a single probe refresh through
+	BreakerHalfOpen = circuit.HalfOpen
+)
+
+const (
+	// DefaultBreakerThreshold is the number of consecutive failures that
+	// opens a breaker
+	DefaultBreakerThreshold = 5
+
+	// DefaultBreakerCooldown is how long an open breaker waits before
+	// allowing a probe
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// CircuitBreaker tracks consecutive failures for a provider so that an
+// unhealthy upstream is not probed on every request
+type CircuitBreaker = circuit.Breaker
+
+// NewCircuitBreaker creates a closed breaker that opens after threshold
+// consecutive failures and probes again after cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return circuit.New(threshold, cooldown)
+}
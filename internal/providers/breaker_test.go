@@ -0,0 +1,21 @@
+package providers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+
+	if breaker.State() != BreakerClosed {
+		t.Errorf("Expected state %s, got %s", BreakerClosed, breaker.State())
+	}
+
+	breaker.RecordFailure()
+	breaker.RecordFailure()
+
+	if breaker.State() != BreakerOpen {
+		t.Errorf("Expected state %s, got %s", BreakerOpen, breaker.State())
+	}
+}
@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// CachedProvider wraps a Provider and memoizes its tool list for a TTL so
+// that expensive dynamic providers are not rebuilt on every discovery
+type CachedProvider struct {
+	Provider
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	tools     []utcp.Tool
+	fetchedAt time.Time
+	valid     bool
+}
+
+// NewCachedProvider wraps provider, caching its tools for ttl
+func NewCachedProvider(provider Provider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		Provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// Unwrap returns the underlying provider
+func (c *CachedProvider) Unwrap() Provider {
+	return c.Provider
+}
+
+// GetTools returns the cached tools, refreshing them once the TTL expires
+func (c *CachedProvider) GetTools() []utcp.Tool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fresh() {
+		c.store(c.Provider.GetTools())
+	}
+
+	return c.copyTools()
+}
+
+// GetToolsContext returns the cached tools, refreshing them through the
+// underlying provider's GetToolsContext when it is async. Failed refreshes
+// are not cached.
+func (c *CachedProvider) GetToolsContext(ctx context.Context) ([]utcp.Tool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fresh() {
+		async, ok := c.Provider.(AsyncProvider)
+		if !ok {
+			c.store(c.Provider.GetTools())
+			return c.copyTools(), nil
+		}
+
+		tools, err := async.GetToolsContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.store(tools)
+	}
+
+	return c.copyTools(), nil
+}
+
+// HealthCheck delegates to the underlying provider when it is checkable
+func (c *CachedProvider) HealthCheck(ctx context.Context) error {
+	if checker, ok := c.Provider.(HealthChecker); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// Fresh reports whether the next call will be served from the cache
+func (c *CachedProvider) Fresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fresh()
+}
+
+// Invalidate discards the cached tools so the next call refreshes them
+func (c *CachedProvider) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tools = nil
+	c.valid = false
+}
+
+// fresh reports whether the cached tools are within the TTL. The caller
+// must hold c.mu.
+func (c *CachedProvider) fresh() bool {
+	return c.valid && c.now().Sub(c.fetchedAt) < c.ttl
+}
+
+// store caches tools. The caller must hold c.mu.
+func (c *CachedProvider) store(tools []utcp.Tool) {
+	c.tools = tools
+	c.fetchedAt = c.now()
+	c.valid = true
+}
+
+// copyTools returns a copy of the cached tools. The caller must hold c.mu.
+func (c *CachedProvider) copyTools() []utcp.Tool {
+	tools := make([]utcp.Tool, len(c.tools))
+	copy(tools, c.tools)
+	return tools
+}
@@ -0,0 +1,136 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func countingProvider(calls *int32) *MockProvider {
+	return &MockProvider{
+		BaseProvider: BaseProvider{Name: "counting", Type: "mock", Enabled: true},
+		ToolsFunc: func() []utcp.Tool {
+			atomic.AddInt32(calls, 1)
+			return []utcp.Tool{{Name: "counted_tool"}}
+		},
+	}
+}
+
+func TestCachedProviderWithinTTL(t *testing.T) {
+	var calls int32
+	cached := NewCachedProvider(countingProvider(&calls), time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cached.GetTools()
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected underlying GetTools to be called once, got %d", calls)
+	}
+
+	if cached.GetName() != "counting" {
+		t.Errorf("Expected name 'counting', got %s", cached.GetName())
+	}
+}
+
+func TestCachedProviderExpiryAndInvalidate(t *testing.T) {
+	var calls int32
+	now := time.Now()
+	cached := NewCachedProvider(countingProvider(&calls), time.Minute)
+	cached.now = func() time.Time { return now }
+
+	if cached.Fresh() {
+		t.Error("Expected an empty cache not to be fresh")
+	}
+
+	cached.GetTools()
+	now = now.Add(30 * time.Second)
+
+	if !cached.Fresh() {
+		t.Error("Expected cache to be fresh within TTL")
+	}
+
+	cached.GetTools()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call within TTL, got %d", calls)
+	}
+
+	now = now.Add(time.Minute)
+
+	if cached.Fresh() {
+		t.Error("Expected cache not to be fresh after TTL expiry")
+	}
+
+	cached.GetTools()
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls after TTL expiry, got %d", calls)
+	}
+
+	cached.Invalidate()
+	cached.GetTools()
+
+	if calls != 3 {
+		t.Errorf("Expected 3 calls after Invalidate, got %d", calls)
+	}
+}
+
+func TestCachedProviderAsyncErrorsNotCached(t *testing.T) {
+	var inFlight, maxSeen int32
+	async := &SlowAsyncProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "async", Enabled: true}},
+		InFlight:     &inFlight,
+		MaxSeen:      &maxSeen,
+		Err:          fmt.Errorf("upstream down"),
+	}
+	cached := NewCachedProvider(async, time.Minute)
+
+	if _, err := cached.GetToolsContext(context.Background()); err == nil {
+		t.Fatal("Expected error from failing provider, got nil")
+	}
+
+	async.Err = nil
+	tools, err := cached.GetToolsContext(context.Background())
+	if err != nil {
+		t.Fatalf("Expected refresh to succeed, got %v", err)
+	}
+
+	if len(tools) != 1 || tools[0].Name != "async_tool" {
+		t.Errorf("Expected [async_tool], got %v", tools)
+	}
+}
+
+func TestRegistryToolCacheTTL(t *testing.T) {
+	var calls int32
+
+	registry := NewRegistry()
+	registry.SetToolCacheTTL(time.Minute)
+	registry.RegisterFactory("counting", func(config map[string]interface{}) (Provider, error) {
+		return countingProvider(&calls), nil
+	})
+	registry.CreateProvider("counting", "counting", map[string]interface{}{})
+
+	provider, _ := registry.GetProvider("counting")
+	if _, ok := provider.(*CachedProvider); !ok {
+		t.Fatalf("Expected provider to be wrapped in CachedProvider, got %T", provider)
+	}
+
+	registry.GetAllTools()
+	registry.GetAllToolsContext(context.Background())
+
+	if calls != 1 {
+		t.Errorf("Expected underlying GetTools to be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"context"
+	"path"
+
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// FilteredProvider wraps a Provider and exposes only a curated subset of its
+// tools, selected by glob patterns on tool names
+type FilteredProvider struct {
+	Provider
+
+	allow []string
+	deny  []string
+}
+
+// NewFilteredProvider wraps provider, keeping tools whose names match an
+// allow pattern and dropping those that match a deny pattern. When allow is
+// set it takes precedence and deny is ignored. Patterns use path.Match
+// syntax, such as "jira_*".
+func NewFilteredProvider(provider Provider, allow, deny []string) (*FilteredProvider, error) {
+	for _, pattern := range append(append([]string(nil), allow...), deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.ValidationErrorf("invalid tool pattern %q", pattern)
+		}
+	}
+
+	return &FilteredProvider{
+		Provider: provider,
+		allow:    allow,
+		deny:     deny,
+	}, nil
+}
+
+// Unwrap returns the underlying provider
+func (p *FilteredProvider) Unwrap() Provider {
+	return p.Provider
+}
+
+// GetTools returns the underlying tools that pass the filter
+func (p *FilteredProvider) GetTools() []utcp.Tool {
+	return p.filter(p.Provider.GetTools())
+}
+
+// GetToolsContext returns the underlying tools that pass the filter,
+// refreshing them through GetToolsContext when the provider is async
+func (p *FilteredProvider) GetToolsContext(ctx context.Context) ([]utcp.Tool, error) {
+	async, ok := p.Provider.(AsyncProvider)
+	if !ok {
+		return p.GetTools(), nil
+	}
+
+	tools, err := async.GetToolsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.filter(tools), nil
+}
+
+// HealthCheck delegates to the underlying provider when it is checkable
+func (p *FilteredProvider) HealthCheck(ctx context.Context) error {
+	if checker, ok := p.Provider.(HealthChecker); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// filter returns the tools allowed by the provider's patterns
+func (p *FilteredProvider) filter(tools []utcp.Tool) []utcp.Tool {
+	filtered := make([]utcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if p.allows(tool.Name) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// allows reports whether a tool name passes the allow or deny list
+func (p *FilteredProvider) allows(name string) bool {
+	if len(p.allow) > 0 {
+		return matchAny(p.allow, name)
+	}
+	return !matchAny(p.deny, name)
+}
+
+// matchAny reports whether name matches any of the glob patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList reads a list of strings from a provider config value, which is
+// []interface{} when decoded from YAML
+func stringList(config map[string]interface{}, key string) ([]string, error) {
+	switch value := config[key].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return value, nil
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, errors.ValidationErrorf("%s must be a list of strings", key)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, errors.ValidationErrorf("%s must be a list of strings", key)
+	}
+}
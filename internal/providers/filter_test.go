@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func filterRegistry() *Registry {
+	registry := NewRegistry()
+	registry.RegisterFactory("mock", func(config map[string]interface{}) (Provider, error) {
+		name, _ := config["name"].(string)
+		return &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Type: "mock", Enabled: true},
+			ToolsFunc: func() []utcp.Tool {
+				return []utcp.Tool{
+					{Name: "jira_get_issue"},
+					{Name: "jira_delete_issue"},
+					{Name: "jira_delete_comment"},
+					{Name: "wiki_get_page"},
+				}
+			},
+		}, nil
+	})
+	return registry
+}
+
+func toolNames(tools []utcp.Tool) map[string]bool {
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+func TestToolsAllow(t *testing.T) {
+	registry := filterRegistry()
+	if err := registry.CreateProvider("p", "mock", map[string]interface{}{
+		"tools_allow": []interface{}{"jira_*"},
+	}); err != nil {
+		t.Fatalf("CreateProvider failed: %v", err)
+	}
+
+	names := toolNames(registry.GetAllTools())
+	if len(names) != 3 || names["wiki_get_page"] {
+		t.Errorf("Expected only jira tools, got %v", names)
+	}
+}
+
+func TestToolsDeny(t *testing.T) {
+	registry := filterRegistry()
+	if err := registry.CreateProvider("p", "mock", map[string]interface{}{
+		"tools_deny": []string{"*_delete_*"},
+	}); err != nil {
+		t.Fatalf("CreateProvider failed: %v", err)
+	}
+
+	names := toolNames(registry.GetAllTools())
+	if len(names) != 2 || !names["jira_get_issue"] || !names["wiki_get_page"] {
+		t.Errorf("Expected delete tools to be dropped, got %v", names)
+	}
+}
+
+func TestToolsAllowTakesPrecedence(t *testing.T) {
+	registry := filterRegistry()
+	if err := registry.CreateProvider("p", "mock", map[string]interface{}{
+		"tools_allow": []interface{}{"jira_delete_*"},
+		"tools_deny":  []interface{}{"jira_delete_issue"},
+		"tool_prefix": "corp_",
+	}); err != nil {
+		t.Fatalf("CreateProvider failed: %v", err)
+	}
+
+	names := toolNames(registry.GetAllTools())
+	if len(names) != 2 || !names["corp_jira_delete_issue"] || !names["corp_jira_delete_comment"] {
+		t.Errorf("Expected allow list to win over deny list, got %v", names)
+	}
+}
+
+func TestToolsFilterInvalid(t *testing.T) {
+	cases := map[string]interface{}{
+		"tools_allow": []interface{}{"jira_["},
+		"tools_deny":  "jira_*",
+	}
+
+	for key, value := range cases {
+		registry := filterRegistry()
+		err := registry.CreateProvider("p", "mock", map[string]interface{}{key: value})
+		if err == nil {
+			t.Errorf("%s: expected error for %v", key, value)
+			continue
+		}
+
+		if !errors.Is(err, errors.ErrorTypeConfiguration) {
+			t.Errorf("%s: expected configuration error, got %v", key, err)
+		}
+
+		if _, exists := registry.GetProvider("p"); exists {
+			t.Errorf("%s: expected provider not to be registered", key)
+		}
+	}
+}
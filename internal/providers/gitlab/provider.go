@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/rh-utcp/rh-utcp/internal/providers"
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
@@ -11,6 +12,9 @@ import (
 type Provider struct {
 	providers.BaseProvider
 	Token string
+
+	// OAuth2 switches tools from a personal token to OAuth2 client credentials
+	OAuth2 *providers.OAuth2Env
 }
 
 // NewProvider creates a new GitLab provider
@@ -36,17 +40,43 @@ func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, e
 		return nil, fmt.Errorf("base_url is required")
 	}
 
-	if token == "" {
+	oauth2, useOAuth2 := providers.OAuth2EnvFromConfig(config, "GITLAB")
+	if !useOAuth2 && token == "" {
 		return nil, fmt.Errorf("token is required for GitLab provider")
 	}
 
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	provider := NewProvider(baseURL, token)
 	provider.Name = name
 	provider.Enabled = enabled
+	provider.Headers = headers
+	if useOAuth2 {
+		provider.OAuth2 = &oauth2
+	}
 
 	return provider, nil
 }
 
+// RequiredEnv returns the environment variables referenced by GitLab tools
+func (p *Provider) RequiredEnv() []string {
+	if p.OAuth2 != nil {
+		return p.OAuth2.Names()
+	}
+	return []string{"GITLAB_TOKEN"}
+}
+
+// auth returns the auth block shared by all GitLab tools
+func (p *Provider) auth() map[string]interface{} {
+	if p.OAuth2 != nil {
+		return p.OAuth2.Auth()
+	}
+	return utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN")
+}
+
 // GetTools returns all available GitLab tools
 func (p *Provider) GetTools() []utcp.Tool {
 	tools := []utcp.Tool{}
@@ -94,11 +124,27 @@ func (p *Provider) GetTools() []utcp.Tool {
 			Description: "List of projects matching the search criteria",
 		},
 		Tags: []string{"gitlab", "projects", "search"},
+		Examples: []utcp.ToolExample{
+			{
+				Name:        "Projects I belong to",
+				Description: "Search by name among projects the token's user is a member of",
+				Input: map[string]interface{}{
+					"search":     "payments",
+					"membership": true,
+					"per_page":   5,
+				},
+				Output: map[string]interface{}{
+					"items": []map[string]interface{}{
+						{"id": 42, "path_with_namespace": "team/payments-api", "visibility": "internal"},
+					},
+				},
+			},
+		},
 		ToolProvider: utcp.HTTPProvider(
 			"gitlab_search_projects",
 			fmt.Sprintf("%s/api/v4/projects", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
 		),
 	})
 
@@ -130,7 +176,8 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"gitlab_get_project",
 			fmt.Sprintf("%s/api/v4/projects/${id}", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
+			utcp.WithTimeout(5*time.Second),
 		),
 	})
 
@@ -186,11 +233,26 @@ func (p *Provider) GetTools() []utcp.Tool {
 			Description: "List of merge requests with details",
 		},
 		Tags: []string{"gitlab", "merge_requests", "list"},
+		Examples: []utcp.ToolExample{
+			{
+				Name: "Open merge requests assigned to me",
+				Input: map[string]interface{}{
+					"project_id": "team%2Fpayments-api",
+					"state":      "opened",
+					"scope":      "assigned_to_me",
+				},
+				Output: map[string]interface{}{
+					"items": []map[string]interface{}{
+						{"iid": 7, "title": "Add retry to webhook sender", "state": "opened"},
+					},
+				},
+			},
+		},
 		ToolProvider: utcp.HTTPProvider(
-			"gitlab_list_mrs",
+			"gitlab_list_merge_requests",
 			fmt.Sprintf("%s/api/v4/projects/${project_id}/merge_requests", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
 		),
 	})
 
@@ -228,10 +290,150 @@ func (p *Provider) GetTools() []utcp.Tool {
 		},
 		Tags: []string{"gitlab", "merge_request", "details"},
 		ToolProvider: utcp.HTTPProvider(
-			"gitlab_get_mr",
+			"gitlab_get_merge_request",
 			fmt.Sprintf("%s/api/v4/projects/${project_id}/merge_requests/${merge_request_iid}", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
+			utcp.WithTimeout(5*time.Second),
+		),
+	})
+
+	// List merge request notes tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_list_mr_notes",
+		Description: "List comments (notes) on a merge request",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"merge_request_iid": {
+					Type:        "integer",
+					Description: "Internal ID of the merge request",
+				},
+				"sort": {
+					Type:        "string",
+					Description: "Return notes in ascending or descending order",
+					Enum:        []string{"asc", "desc"},
+					Default:     "desc",
+				},
+				"order_by": {
+					Type:        "string",
+					Description: "Order notes by field",
+					Enum:        []string{"created_at", "updated_at"},
+					Default:     "created_at",
+				},
+				"per_page": {
+					Type:        "integer",
+					Description: "Number of results per page",
+					Default:     20,
+				},
+			},
+			Required: []string{"project_id", "merge_request_iid"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "array",
+			Description: "List of notes with author, body, and timestamps",
+		},
+		Tags: []string{"gitlab", "merge_request", "notes"},
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_list_mr_notes",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/merge_requests/${merge_request_iid}/notes", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// Create merge request note tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_create_mr_note",
+		Description: "Add a comment (note) to a merge request",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"merge_request_iid": {
+					Type:        "integer",
+					Description: "Internal ID of the merge request",
+				},
+				"body": {
+					Type:        "string",
+					Description: "Content of the note (Markdown supported)",
+				},
+			},
+			Required: []string{"project_id", "merge_request_iid", "body"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Created note",
+		},
+		Tags: []string{"gitlab", "merge_request", "notes"},
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_create_mr_note",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/merge_requests/${merge_request_iid}/notes", p.BaseURL),
+			"POST",
+			p.auth(),
+		),
+	})
+
+	// Create merge request tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_create_merge_request",
+		Description: "Open a new merge request",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"source_branch": {
+					Type:        "string",
+					Description: "Branch containing the changes",
+				},
+				"target_branch": {
+					Type:        "string",
+					Description: "Branch to merge into",
+				},
+				"title": {
+					Type:        "string",
+					Description: "Title of the merge request",
+				},
+				"description": {
+					Type:        "string",
+					Description: "Description of the merge request (Markdown supported)",
+				},
+				"assignee_id": {
+					Type:        "integer",
+					Description: "User ID to assign the merge request to",
+				},
+				"labels": {
+					Type:        "string",
+					Description: "Comma-separated list of label names",
+				},
+				"remove_source_branch": {
+					Type:        "boolean",
+					Description: "Delete the source branch when the merge request is merged",
+				},
+			},
+			Required:             []string{"project_id", "source_branch", "target_branch", "title"},
+			AdditionalProperties: utcp.Bool(false),
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Created merge request",
+		},
+		Tags: []string{"gitlab", "merge_request", "create"},
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_create_merge_request",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/merge_requests", p.BaseURL),
+			"POST",
+			p.auth(),
 		),
 	})
 
@@ -292,7 +494,112 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"gitlab_list_issues",
 			fmt.Sprintf("%s/api/v4/issues", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
+		),
+	})
+
+	// Create issue tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_create_issue",
+		Description: "Create a new issue in a project",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"title": {
+					Type:        "string",
+					Description: "Title of the issue",
+				},
+				"description": {
+					Type:        "string",
+					Description: "Description of the issue (Markdown supported)",
+				},
+				"labels": {
+					Type:        "string",
+					Description: "Comma-separated list of label names",
+				},
+				"assignee_id": {
+					Type:        "integer",
+					Description: "User ID to assign the issue to",
+				},
+				"milestone_id": {
+					Type:        "integer",
+					Description: "Milestone ID to assign the issue to",
+				},
+				"confidential": {
+					Type:        "boolean",
+					Description: "Create the issue as confidential",
+				},
+			},
+			Required:             []string{"project_id", "title"},
+			AdditionalProperties: utcp.Bool(false),
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Created issue",
+		},
+		Tags: []string{"gitlab", "issues", "create"},
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_create_issue",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/issues", p.BaseURL),
+			"POST",
+			p.auth(),
+		),
+	})
+
+	// Update issue tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_update_issue",
+		Description: "Update an existing issue",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"issue_iid": {
+					Type:        "integer",
+					Description: "Internal ID of the issue",
+				},
+				"title": {
+					Type:        "string",
+					Description: "New title of the issue",
+				},
+				"description": {
+					Type:        "string",
+					Description: "New description of the issue (Markdown supported)",
+				},
+				"labels": {
+					Type:        "string",
+					Description: "Comma-separated list of label names, replacing existing labels",
+				},
+				"assignee_id": {
+					Type:        "integer",
+					Description: "User ID to assign the issue to",
+				},
+				"state_event": {
+					Type:        "string",
+					Description: "Close or reopen the issue",
+					Enum:        []string{"close", "reopen"},
+				},
+			},
+			Required:             []string{"project_id", "issue_iid"},
+			AdditionalProperties: utcp.Bool(false),
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Updated issue",
+		},
+		Tags: []string{"gitlab", "issues", "update"},
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_update_issue",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/issues/${issue_iid}", p.BaseURL),
+			"PUT",
+			p.auth(),
 		),
 	})
 
@@ -328,7 +635,7 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"gitlab_get_file",
 			fmt.Sprintf("%s/api/v4/projects/${project_id}/repository/files/${file_path}", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
 		),
 	})
 
@@ -371,10 +678,10 @@ func (p *Provider) GetTools() []utcp.Tool {
 		},
 		Tags: []string{"gitlab", "repository", "tree"},
 		ToolProvider: utcp.HTTPProvider(
-			"gitlab_list_tree",
+			"gitlab_list_repository_tree",
 			fmt.Sprintf("%s/api/v4/projects/${project_id}/repository/tree", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
 		),
 	})
 
@@ -406,6 +713,16 @@ func (p *Provider) GetTools() []utcp.Tool {
 					Type:        "string",
 					Description: "Filter by username of pipeline triggerer",
 				},
+				"updated_after": {
+					Type:        "string",
+					Description: "Return pipelines updated after this time",
+					Format:      "date-time",
+				},
+				"updated_before": {
+					Type:        "string",
+					Description: "Return pipelines updated before this time",
+					Format:      "date-time",
+				},
 				"per_page": {
 					Type:        "integer",
 					Description: "Results per page",
@@ -423,7 +740,7 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"gitlab_list_pipelines",
 			fmt.Sprintf("%s/api/v4/projects/${project_id}/pipelines", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
 		),
 	})
 
@@ -454,7 +771,82 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"gitlab_get_pipeline",
 			fmt.Sprintf("%s/api/v4/projects/${project_id}/pipelines/${pipeline_id}", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
+			utcp.WithTimeout(5*time.Second),
+		),
+	})
+
+	// List project variables tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_list_project_variables",
+		Description: "List CI/CD variables defined for a project. Values may contain secrets.",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"per_page": {
+					Type:        "integer",
+					Description: "Results per page",
+					Default:     20,
+				},
+			},
+			Required: []string{"project_id"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "array",
+			Description: "List of variables with key, value, scope and protection flags",
+		},
+		Tags:      []string{"gitlab", "ci/cd", "variables"},
+		Sensitive: true,
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_list_project_variables",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/variables", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// List environments tool
+	tools = append(tools, utcp.Tool{
+		Name:        "gitlab_list_environments",
+		Description: "List deployment environments for a project",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "string",
+					Description: "Project ID or URL-encoded path",
+				},
+				"states": {
+					Type:        "string",
+					Description: "Filter by environment state",
+					Enum:        []string{"available", "stopping", "stopped"},
+				},
+				"search": {
+					Type:        "string",
+					Description: "Search environments by name",
+				},
+				"per_page": {
+					Type:        "integer",
+					Description: "Results per page",
+					Default:     20,
+				},
+			},
+			Required: []string{"project_id"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "array",
+			Description: "List of environments with state and last deployment",
+		},
+		Tags: []string{"gitlab", "ci/cd", "environments"},
+		ToolProvider: utcp.HTTPProvider(
+			"gitlab_list_environments",
+			fmt.Sprintf("%s/api/v4/projects/${project_id}/environments", p.BaseURL),
+			"GET",
+			p.auth(),
 		),
 	})
 
@@ -487,14 +879,15 @@ func (p *Provider) GetTools() []utcp.Tool {
 			Type:        "array",
 			Description: "Search results with file paths and matching content",
 		},
-		Tags: []string{"gitlab", "search", "code"},
+		Tags:             []string{"gitlab", "search", "code"},
+		AverageLatencyMs: 3000,
 		ToolProvider: utcp.HTTPProvider(
 			"gitlab_search_code",
 			fmt.Sprintf("%s/api/v4/search", p.BaseURL),
 			"GET",
-			utcp.PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN"),
+			p.auth(),
 		),
 	})
 
-	return tools
+	return utcp.WithInputLocations(utcp.WithDefaultRetry(utcp.ApplyHeaders(tools, p.Headers)))
 }
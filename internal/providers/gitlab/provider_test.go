@@ -1,6 +1,8 @@
 package gitlab
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
@@ -25,6 +27,58 @@ func TestNewProvider(t *testing.T) {
 	}
 }
 
+func TestRequiredEnv(t *testing.T) {
+	provider := NewProvider("https://gitlab.example.com", "test-token")
+
+	expected := []string{"GITLAB_TOKEN"}
+	required := provider.RequiredEnv()
+
+	if len(required) != len(expected) {
+		t.Fatalf("Expected %d required env vars, got %d", len(expected), len(required))
+	}
+
+	for i, name := range expected {
+		if required[i] != name {
+			t.Errorf("Expected required env var %s, got %s", name, required[i])
+		}
+	}
+}
+
+func TestOAuth2FromConfig(t *testing.T) {
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"name":          "gitlab-oauth",
+		"enabled":       true,
+		"base_url":      "https://gitlab.example.com",
+		"auth_type":     "oauth2",
+		"token_url_env": "SSO_TOKEN_URL",
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	for _, tool := range provider.GetTools() {
+		auth, _ := tool.ToolProvider["auth"].(map[string]interface{})
+		if auth["auth_type"] != "oauth2" {
+			t.Fatalf("Tool %s: expected oauth2 auth, got %v", tool.Name, auth["auth_type"])
+		}
+
+		if auth["client_id"] != "$GITLAB_CLIENT_ID" || auth["client_secret"] != "$GITLAB_CLIENT_SECRET" || auth["token_url"] != "$SSO_TOKEN_URL" {
+			t.Errorf("Tool %s: expected oauth2 env references, got %v", tool.Name, auth)
+		}
+	}
+
+	expected := []string{"GITLAB_CLIENT_ID", "GITLAB_CLIENT_SECRET", "SSO_TOKEN_URL"}
+	required := provider.RequiredEnv()
+	if strings.Join(required, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected required env %v, got %v", expected, required)
+	}
+
+	// Without oauth2 tools keep the default GitLab auth
+	if auth := NewProvider("https://gitlab.example.com", "token").GetTools()[0].ToolProvider["auth"].(map[string]interface{}); auth["auth_type"] != "personal_token" {
+		t.Errorf("Expected personal_token auth by default, got %v", auth["auth_type"])
+	}
+}
+
 func TestGetTools(t *testing.T) {
 	provider := NewProvider("https://gitlab.example.com", "test-token")
 	tools := provider.GetTools()
@@ -35,16 +89,23 @@ func TestGetTools(t *testing.T) {
 
 	// Expected tools
 	expectedTools := map[string]bool{
-		"gitlab_search_projects":      false,
-		"gitlab_get_project":          false,
-		"gitlab_list_merge_requests":  false,
-		"gitlab_get_merge_request":    false,
-		"gitlab_list_issues":          false,
-		"gitlab_get_file":             false,
-		"gitlab_list_repository_tree": false,
-		"gitlab_list_pipelines":       false,
-		"gitlab_get_pipeline":         false,
-		"gitlab_search_code":          false,
+		"gitlab_search_projects":        false,
+		"gitlab_get_project":            false,
+		"gitlab_list_merge_requests":    false,
+		"gitlab_get_merge_request":      false,
+		"gitlab_list_mr_notes":          false,
+		"gitlab_create_mr_note":         false,
+		"gitlab_create_merge_request":   false,
+		"gitlab_create_issue":           false,
+		"gitlab_update_issue":           false,
+		"gitlab_list_issues":            false,
+		"gitlab_get_file":               false,
+		"gitlab_list_repository_tree":   false,
+		"gitlab_list_pipelines":         false,
+		"gitlab_get_pipeline":           false,
+		"gitlab_list_project_variables": false,
+		"gitlab_list_environments":      false,
+		"gitlab_search_code":            false,
 	}
 
 	// Check all expected tools are present
@@ -204,6 +265,162 @@ func TestGitLabListMergeRequestsTool(t *testing.T) {
 	}
 }
 
+func TestGitLabSearchProjectsExamples(t *testing.T) {
+	provider := NewProvider("https://gitlab.example.com", "test-token")
+
+	var searchTool *utcp.Tool
+	for _, tool := range provider.GetTools() {
+		if tool.Name == "gitlab_search_projects" {
+			searchTool = &tool
+			break
+		}
+	}
+
+	if searchTool == nil {
+		t.Fatal("gitlab_search_projects tool not found")
+	}
+
+	if len(searchTool.Examples) == 0 {
+		t.Fatal("Expected at least one example for gitlab_search_projects")
+	}
+
+	for _, example := range searchTool.Examples {
+		if example.Description == "" {
+			t.Errorf("Example '%s' is missing a description", example.Name)
+		}
+
+		for name := range example.Input {
+			if _, exists := searchTool.Inputs.Properties[name]; !exists {
+				t.Errorf("Example '%s' uses undeclared input %s", example.Name, name)
+			}
+		}
+	}
+}
+
+func TestGitLabSensitiveTools(t *testing.T) {
+	provider := NewProvider("https://gitlab.example.com", "test-token")
+
+	for _, tool := range provider.GetTools() {
+		wantSensitive := tool.Name == "gitlab_list_project_variables"
+		if tool.Sensitive != wantSensitive {
+			t.Errorf("Tool %s: expected sensitive %v, got %v", tool.Name, wantSensitive, tool.Sensitive)
+		}
+
+		data, err := json.Marshal(tool)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+
+		if got := strings.Contains(string(data), `"sensitive":true`); got != wantSensitive {
+			t.Errorf("Tool %s: expected sensitive in JSON %v, got %s", tool.Name, wantSensitive, data)
+		}
+	}
+}
+
+func TestGitLabWriteTools(t *testing.T) {
+	provider := NewProvider("https://gitlab.example.com", "test-token")
+	tools := provider.GetTools()
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		required []string
+	}{
+		{
+			name:     "gitlab_create_issue",
+			method:   "POST",
+			url:      "https://gitlab.example.com/api/v4/projects/${project_id}/issues",
+			required: []string{"project_id", "title"},
+		},
+		{
+			name:     "gitlab_update_issue",
+			method:   "PUT",
+			url:      "https://gitlab.example.com/api/v4/projects/${project_id}/issues/${issue_iid}",
+			required: []string{"project_id", "issue_iid"},
+		},
+		{
+			name:     "gitlab_create_merge_request",
+			method:   "POST",
+			url:      "https://gitlab.example.com/api/v4/projects/${project_id}/merge_requests",
+			required: []string{"project_id", "source_branch", "target_branch", "title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var found *utcp.Tool
+			for i := range tools {
+				if tools[i].Name == tt.name {
+					found = &tools[i]
+					break
+				}
+			}
+
+			if found == nil {
+				t.Fatalf("%s tool not found", tt.name)
+			}
+
+			if found.ToolProvider["http_method"] != tt.method {
+				t.Errorf("Expected http_method '%s', got %v", tt.method, found.ToolProvider["http_method"])
+			}
+
+			if found.ToolProvider["url"] != tt.url {
+				t.Errorf("Expected URL %s, got %v", tt.url, found.ToolProvider["url"])
+			}
+
+			if len(found.Inputs.Required) != len(tt.required) {
+				t.Fatalf("Expected %d required fields, got %d", len(tt.required), len(found.Inputs.Required))
+			}
+			for i, field := range tt.required {
+				if found.Inputs.Required[i] != field {
+					t.Errorf("Expected required field %s, got %s", field, found.Inputs.Required[i])
+				}
+			}
+
+			if found.Inputs.AdditionalProperties == nil || *found.Inputs.AdditionalProperties {
+				t.Error("Expected additionalProperties to be false")
+			}
+		})
+	}
+}
+
+func TestGitLabCreateMRNoteTool(t *testing.T) {
+	provider := NewProvider("https://gitlab.example.com", "test-token")
+	tools := provider.GetTools()
+
+	var noteTool *utcp.Tool
+	for _, tool := range tools {
+		if tool.Name == "gitlab_create_mr_note" {
+			noteTool = &tool
+			break
+		}
+	}
+
+	if noteTool == nil {
+		t.Fatal("gitlab_create_mr_note tool not found")
+	}
+
+	if noteTool.ToolProvider["http_method"] != "POST" {
+		t.Errorf("Expected http_method 'POST', got %v", noteTool.ToolProvider["http_method"])
+	}
+
+	expectedRequired := []string{"project_id", "merge_request_iid", "body"}
+	if len(noteTool.Inputs.Required) != len(expectedRequired) {
+		t.Fatalf("Expected %d required fields, got %d", len(expectedRequired), len(noteTool.Inputs.Required))
+	}
+	for i, field := range expectedRequired {
+		if noteTool.Inputs.Required[i] != field {
+			t.Errorf("Expected required field %s, got %s", field, noteTool.Inputs.Required[i])
+		}
+	}
+
+	expectedURL := "https://gitlab.example.com/api/v4/projects/${project_id}/merge_requests/${merge_request_iid}/notes"
+	if noteTool.ToolProvider["url"] != expectedURL {
+		t.Errorf("Expected URL %s, got %v", expectedURL, noteTool.ToolProvider["url"])
+	}
+}
+
 func TestGitLabListIssuesTool(t *testing.T) {
 	provider := NewProvider("https://gitlab.example.com", "test-token")
 	tools := provider.GetTools()
@@ -309,6 +526,13 @@ func TestGitLabListPipelinesTool(t *testing.T) {
 	if !hasCITag {
 		t.Error("Missing 'ci/cd' tag")
 	}
+
+	// Check date filters carry a format hint
+	for _, name := range []string{"updated_after", "updated_before"} {
+		if format := pipelineTool.Inputs.Properties[name].Format; format != "date-time" {
+			t.Errorf("Expected '%s' format 'date-time', got '%s'", name, format)
+		}
+	}
 }
 
 func TestGitLabSearchCodeTool(t *testing.T) {
@@ -341,6 +565,11 @@ func TestGitLabSearchCodeTool(t *testing.T) {
 	if len(scopeProp.Enum) != 2 {
 		t.Errorf("Expected 2 scope options, got %d", len(scopeProp.Enum))
 	}
+
+	// Check latency hint
+	if searchTool.AverageLatencyMs != 3000 {
+		t.Errorf("Expected average latency 3000ms, got %d", searchTool.AverageLatencyMs)
+	}
 }
 
 func TestAllToolsHaveValidProviders(t *testing.T) {
@@ -365,11 +594,22 @@ func TestAllToolsHaveValidProviders(t *testing.T) {
 			t.Errorf("Tool %s has empty outputs type", tool.Name)
 		}
 
+		// Check enum defaults are valid options
+		for name, property := range tool.Inputs.Properties {
+			if err := property.Validate(); err != nil {
+				t.Errorf("Tool %s input %s: %v", tool.Name, name, err)
+			}
+		}
+
 		// Check provider configuration
 		if tool.ToolProvider == nil {
 			t.Errorf("Tool %s has nil ToolProvider", tool.Name)
 		}
 
+		if id := tool.ToolProvider["provider_id"]; id != tool.Name {
+			t.Errorf("Tool %s has mismatched provider_id %v", tool.Name, id)
+		}
+
 		providerType, ok := tool.ToolProvider["provider_type"].(string)
 		if !ok || providerType != "http" {
 			t.Errorf("Tool %s has invalid provider_type", tool.Name)
@@ -381,7 +621,7 @@ func TestAllToolsHaveValidProviders(t *testing.T) {
 		}
 
 		method, ok := tool.ToolProvider["http_method"].(string)
-		if !ok || method != "GET" {
+		if !ok || !utcp.ValidHTTPMethod(method) {
 			t.Errorf("Tool %s has invalid HTTP method: %s", tool.Name, method)
 		}
 
@@ -2,6 +2,8 @@ package jira
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/rh-utcp/rh-utcp/internal/providers"
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
@@ -12,6 +14,12 @@ type Provider struct {
 	providers.BaseProvider
 	Username string
 	Password string
+
+	// OAuth2 switches tools from basic auth to OAuth2 client credentials
+	OAuth2 *providers.OAuth2Env
+
+	// IssueTemplates adds one create-issue tool per template
+	IssueTemplates []IssueTemplate
 }
 
 // NewProvider creates a new Jira provider
@@ -39,17 +47,49 @@ func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, e
 		return nil, fmt.Errorf("base_url is required")
 	}
 
-	if username == "" || password == "" {
+	oauth2, useOAuth2 := providers.OAuth2EnvFromConfig(config, "JIRA")
+	if !useOAuth2 && (username == "" || password == "") {
 		return nil, fmt.Errorf("username and password are required for Jira provider")
 	}
 
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := IssueTemplatesFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	provider := NewProvider(baseURL, username, password)
 	provider.Name = name
 	provider.Enabled = enabled
+	provider.Headers = headers
+	provider.IssueTemplates = templates
+	if useOAuth2 {
+		provider.OAuth2 = &oauth2
+	}
 
 	return provider, nil
 }
 
+// RequiredEnv returns the environment variables referenced by Jira tools
+func (p *Provider) RequiredEnv() []string {
+	if p.OAuth2 != nil {
+		return p.OAuth2.Names()
+	}
+	return []string{"JIRA_USERNAME", "JIRA_PASSWORD"}
+}
+
+// auth returns the auth block shared by all Jira tools
+func (p *Provider) auth() map[string]interface{} {
+	if p.OAuth2 != nil {
+		return p.OAuth2.Auth()
+	}
+	return utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD")
+}
+
 // GetTools returns all available Jira tools
 func (p *Provider) GetTools() []utcp.Tool {
 	tools := []utcp.Tool{}
@@ -87,11 +127,36 @@ func (p *Provider) GetTools() []utcp.Tool {
 			Description: "Search results containing issues and metadata",
 		},
 		Tags: []string{"jira", "search", "issues"},
+		Examples: []utcp.ToolExample{
+			{
+				Name:        "Open bugs in a project",
+				Description: "Find open bugs with JQL, returning only the fields needed for triage",
+				Input: map[string]interface{}{
+					"jql":        "project = PROJ AND issuetype = Bug AND status = Open",
+					"fields":     []string{"summary", "status", "assignee"},
+					"maxResults": 10,
+				},
+				Output: map[string]interface{}{
+					"startAt":    0,
+					"maxResults": 10,
+					"total":      1,
+					"issues": []map[string]interface{}{
+						{
+							"key": "PROJ-123",
+							"fields": map[string]interface{}{
+								"summary": "Login page returns 500",
+								"status":  map[string]interface{}{"name": "Open"},
+							},
+						},
+					},
+				},
+			},
+		},
 		ToolProvider: utcp.HTTPProvider(
-			"jira_search",
+			"jira_search_issues",
 			fmt.Sprintf("%s/rest/api/2/search", p.BaseURL),
 			"GET",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
 		),
 	})
 
@@ -122,11 +187,28 @@ func (p *Provider) GetTools() []utcp.Tool {
 			Description: "Complete issue details",
 		},
 		Tags: []string{"jira", "issue", "get"},
+		Examples: []utcp.ToolExample{
+			{
+				Name: "Issue with changelog",
+				Input: map[string]interface{}{
+					"issueKey": "PROJ-123",
+					"expand":   []string{"changelog"},
+				},
+				Output: map[string]interface{}{
+					"key": "PROJ-123",
+					"fields": map[string]interface{}{
+						"summary": "Login page returns 500",
+					},
+					"changelog": map[string]interface{}{"histories": []interface{}{}},
+				},
+			},
+		},
 		ToolProvider: utcp.HTTPProvider(
 			"jira_get_issue",
 			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}", p.BaseURL),
 			"GET",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
+			utcp.WithTimeout(5*time.Second),
 		),
 	})
 
@@ -165,8 +247,14 @@ func (p *Provider) GetTools() []utcp.Tool {
 					Type:        "array",
 					Description: "Labels to add to the issue",
 				},
+				"duedate": {
+					Type:        "string",
+					Description: "Due date (e.g., '2024-06-30')",
+					Format:      "date",
+				},
 			},
-			Required: []string{"project", "summary", "issuetype"},
+			Required:             []string{"project", "summary", "issuetype"},
+			AdditionalProperties: utcp.Bool(false),
 		},
 		Outputs: utcp.Schema{
 			Type:        "object",
@@ -177,7 +265,7 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"jira_create_issue",
 			fmt.Sprintf("%s/rest/api/2/issue", p.BaseURL),
 			"POST",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
 		),
 	})
 
@@ -201,7 +289,8 @@ func (p *Provider) GetTools() []utcp.Tool {
 					Description: "Update operations (add, set, remove)",
 				},
 			},
-			Required: []string{"issueKey"},
+			Required:             []string{"issueKey"},
+			AdditionalProperties: utcp.Bool(false),
 		},
 		Outputs: utcp.Schema{
 			Type:        "object",
@@ -212,7 +301,71 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"jira_update_issue",
 			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}", p.BaseURL),
 			"PUT",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
+		),
+	})
+
+	// Get transitions tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_get_transitions",
+		Description: "List the workflow transitions available for a Jira issue in its current status",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {
+					Type:        "string",
+					Description: "Issue key (e.g., 'PROJ-123')",
+				},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Available transitions with their IDs, names and target statuses",
+		},
+		Tags: []string{"jira", "issue", "transitions", "workflow"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_get_transitions",
+			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}/transitions", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// Do transition tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_do_transition",
+		Description: "Move a Jira issue through a workflow transition, e.g. to close or reopen it. Transition IDs come from jira_get_transitions.",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {
+					Type:        "string",
+					Description: "Issue key to transition",
+				},
+				"transition": {
+					Type:        "object",
+					Description: "Transition to perform (e.g., {'id': '31'})",
+				},
+				"fields": {
+					Type:        "object",
+					Description: "Fields required by the transition screen, such as resolution",
+				},
+			},
+			Required:             []string{"issueKey", "transition"},
+			AdditionalProperties: utcp.Bool(false),
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Empty response on success",
+		},
+		Tags:      []string{"jira", "issue", "transitions", "workflow"},
+		DependsOn: []string{"jira_get_transitions"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_do_transition",
+			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}/transitions", p.BaseURL),
+			"POST",
+			p.auth(),
 		),
 	})
 
@@ -242,7 +395,7 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"jira_get_projects",
 			fmt.Sprintf("%s/rest/api/2/project", p.BaseURL),
 			"GET",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
 		),
 	})
 
@@ -277,7 +430,130 @@ func (p *Provider) GetTools() []utcp.Tool {
 			"jira_add_comment",
 			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}/comment", p.BaseURL),
 			"POST",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
+		),
+	})
+
+	// Get attachments tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_get_attachments",
+		Description: "List the attachments on a Jira issue",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {
+					Type:        "string",
+					Description: "Issue key (e.g., 'PROJ-123')",
+				},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Issue with its attachment field (filename, size, author, content URL)",
+		},
+		Tags: []string{"jira", "attachments", "issues"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_get_attachments",
+			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}?fields=attachment", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// Get issue changelog tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_get_issue_changelog",
+		Description: "Get the change history of a Jira issue (field changes with author and time)",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {
+					Type:        "string",
+					Description: "Issue key (e.g., 'PROJ-123')",
+				},
+				"startAt": {
+					Type:        "integer",
+					Description: "Index of the first history entry to return",
+					Default:     0,
+				},
+				"maxResults": {
+					Type:        "integer",
+					Description: "Maximum number of history entries to return",
+					Default:     100,
+				},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Paginated list of history entries, each with author, created time, and changed items",
+		},
+		Tags: []string{"jira", "issue", "history"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_get_issue_changelog",
+			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}/changelog", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// List watchers tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_list_watchers",
+		Description: "List the users watching a Jira issue",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {
+					Type:        "string",
+					Description: "Issue key (e.g., 'PROJ-123')",
+				},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Watch count and list of watching users",
+		},
+		Tags: []string{"jira", "watchers", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_list_watchers",
+			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}/watchers", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// Add watcher tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_add_watcher",
+		Description: "Add a user as a watcher of a Jira issue. Jira takes the username as the whole request body, a bare JSON string such as \"jsmith\".",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {
+					Type:        "string",
+					Description: "Issue key to watch",
+				},
+				"username": {
+					Type:        "string",
+					Description: "Username to add as a watcher, sent as the request body (defaults to the current user when omitted)",
+				},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Empty response on success",
+		},
+		Tags: []string{"jira", "watchers", "add"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_add_watcher",
+			fmt.Sprintf("%s/rest/api/2/issue/${issueKey}/watchers", p.BaseURL),
+			"POST",
+			p.auth(),
+			utcp.WithBodyField("username"),
 		),
 	})
 
@@ -315,12 +591,220 @@ func (p *Provider) GetTools() []utcp.Tool {
 		},
 		Tags: []string{"jira", "user", "issues"},
 		ToolProvider: utcp.HTTPProvider(
-			"jira_user_issues",
+			"jira_get_user_issues",
 			fmt.Sprintf("%s/rest/api/2/search", p.BaseURL),
 			"GET",
-			utcp.BasicAuth("JIRA_USERNAME", "JIRA_PASSWORD"),
+			p.auth(),
+		),
+	})
+
+	// List filters tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_list_filters",
+		Description: "List the current user's favourite saved Jira filters",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"expand": {
+					Type:        "string",
+					Description: "Comma-separated list of additional filter data to expand (e.g., 'sharedUsers,subscriptions')",
+				},
+			},
+		},
+		Outputs: utcp.Schema{
+			Type:        "array",
+			Description: "List of filters with ID, name, and JQL",
+		},
+		Tags: []string{"jira", "filters", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_list_filters",
+			fmt.Sprintf("%s/rest/api/2/filter/favourite", p.BaseURL),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// Run filter tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_run_filter",
+		Description: "Search for Jira issues matching a saved filter's JQL",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"filterId": {
+					Type:        "string",
+					Description: "Saved filter ID (e.g., '10042')",
+				},
+				"maxResults": {
+					Type:        "integer",
+					Description: "Maximum number of results to return",
+					Default:     50,
+				},
+			},
+			Required: []string{"filterId"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Search results containing issues and metadata",
+		},
+		Tags: []string{"jira", "filters", "search"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_run_filter",
+			fmt.Sprintf("%s/rest/api/2/search?jql=filter=${filterId}", p.BaseURL),
+			"GET",
+			p.auth(),
 		),
 	})
 
-	return tools
+	// List boards tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_list_boards",
+		Description: "List Jira Agile (Scrum and Kanban) boards",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"projectKeyOrId": {
+					Type:        "string",
+					Description: "Only boards for this project key or ID",
+				},
+				"type": {
+					Type:        "string",
+					Description: "Board type",
+					Enum:        []string{"scrum", "kanban"},
+				},
+				"name": {
+					Type:        "string",
+					Description: "Only boards whose name contains this text",
+				},
+				"startAt": {
+					Type:        "integer",
+					Description: "Index of the first board to return",
+					Default:     0,
+				},
+				"maxResults": {
+					Type:        "integer",
+					Description: "Maximum number of boards to return",
+					Default:     50,
+				},
+			},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Paginated list of boards with ID, name, and type",
+		},
+		Tags: []string{"jira", "agile", "boards"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_list_boards",
+			p.agileURL("/board"),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// List sprints tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_list_sprints",
+		Description: "List the sprints of a Scrum board",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"boardId": {
+					Type:        "integer",
+					Description: "Board ID",
+				},
+				"state": {
+					Type:        "string",
+					Description: "Comma-separated sprint states to include (future, active, closed)",
+				},
+				"startAt": {
+					Type:        "integer",
+					Description: "Index of the first sprint to return",
+					Default:     0,
+				},
+				"maxResults": {
+					Type:        "integer",
+					Description: "Maximum number of sprints to return",
+					Default:     50,
+				},
+			},
+			Required: []string{"boardId"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Paginated list of sprints with ID, name, state, and dates",
+		},
+		Tags: []string{"jira", "agile", "sprints"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_list_sprints",
+			p.agileURL("/board/${boardId}/sprint"),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	// Get sprint issues tool
+	tools = append(tools, utcp.Tool{
+		Name:        "jira_get_sprint_issues",
+		Description: "List the issues in a sprint",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"sprintId": {
+					Type:        "integer",
+					Description: "Sprint ID",
+				},
+				"jql": {
+					Type:        "string",
+					Description: "Additional JQL to filter the sprint's issues",
+				},
+				"fields": {
+					Type:        "array",
+					Description: "Fields to return for each issue",
+				},
+				"startAt": {
+					Type:        "integer",
+					Description: "Index of the first issue to return",
+					Default:     0,
+				},
+				"maxResults": {
+					Type:        "integer",
+					Description: "Maximum number of issues to return",
+					Default:     50,
+				},
+			},
+			Required: []string{"sprintId"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Search results containing the sprint's issues",
+		},
+		Tags: []string{"jira", "agile", "sprints", "issues"},
+		ToolProvider: utcp.HTTPProvider(
+			"jira_get_sprint_issues",
+			p.agileURL("/sprint/${sprintId}/issue"),
+			"GET",
+			p.auth(),
+		),
+	})
+
+	for _, tool := range tools {
+		if tool.Name != "jira_create_issue" {
+			continue
+		}
+		for _, template := range p.IssueTemplates {
+			tools = append(tools, p.createFromTemplateTool(template, tool))
+		}
+		break
+	}
+
+	return utcp.WithInputLocations(utcp.WithDefaultRetry(utcp.ApplyHeaders(tools, p.Headers)))
+}
+
+// agileAPIPath is the base path of the Jira Agile REST API, which is served
+// separately from the core /rest/api/2 API
+const agileAPIPath = "/rest/agile/1.0"
+
+// agileURL returns the URL of a Jira Agile API endpoint
+func (p *Provider) agileURL(path string) string {
+	return strings.TrimRight(p.BaseURL, "/") + agileAPIPath + path
 }
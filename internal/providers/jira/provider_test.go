@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -31,6 +32,86 @@ func TestNewProvider(t *testing.T) {
 	}
 }
 
+func TestRequiredEnv(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	expected := []string{"JIRA_USERNAME", "JIRA_PASSWORD"}
+	required := provider.RequiredEnv()
+
+	if len(required) != len(expected) {
+		t.Fatalf("Expected %d required env vars, got %d", len(expected), len(required))
+	}
+
+	for i, name := range expected {
+		if required[i] != name {
+			t.Errorf("Expected required env var %s, got %s", name, required[i])
+		}
+	}
+}
+
+func TestOAuth2FromConfig(t *testing.T) {
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"name":          "jira-oauth",
+		"enabled":       true,
+		"base_url":      "https://jira.example.com",
+		"auth_type":     "oauth2",
+		"token_url_env": "SSO_TOKEN_URL",
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	for _, tool := range provider.GetTools() {
+		auth, _ := tool.ToolProvider["auth"].(map[string]interface{})
+		if auth["auth_type"] != "oauth2" {
+			t.Fatalf("Tool %s: expected oauth2 auth, got %v", tool.Name, auth["auth_type"])
+		}
+
+		if auth["client_id"] != "$JIRA_CLIENT_ID" || auth["client_secret"] != "$JIRA_CLIENT_SECRET" || auth["token_url"] != "$SSO_TOKEN_URL" {
+			t.Errorf("Tool %s: expected oauth2 env references, got %v", tool.Name, auth)
+		}
+	}
+
+	expected := []string{"JIRA_CLIENT_ID", "JIRA_CLIENT_SECRET", "SSO_TOKEN_URL"}
+	required := provider.RequiredEnv()
+	if strings.Join(required, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected required env %v, got %v", expected, required)
+	}
+
+	// Without oauth2 tools keep the default Jira auth
+	if auth := NewProvider("https://jira.example.com", "user", "pass").GetTools()[0].ToolProvider["auth"].(map[string]interface{}); auth["auth_type"] != "basic" {
+		t.Errorf("Expected basic auth by default, got %v", auth["auth_type"])
+	}
+}
+
+func TestInputLocations(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	expected := map[string]string{
+		"jira_search_issues": utcp.InputLocationQuery,
+		"jira_create_issue":  utcp.InputLocationBody,
+		"jira_update_issue":  utcp.InputLocationMixed,
+	}
+
+	for _, tool := range provider.GetTools() {
+		if tool.InputLocation == "" {
+			t.Errorf("Tool %s: expected an input location", tool.Name)
+		}
+
+		if want, ok := expected[tool.Name]; ok && tool.InputLocation != want {
+			t.Errorf("Tool %s: expected input location '%s', got '%s'", tool.Name, want, tool.InputLocation)
+		}
+	}
+
+	data, err := json.Marshal(provider.GetTools()[0])
+	if err != nil {
+		t.Fatalf("Failed to marshal tool: %v", err)
+	}
+	if !strings.Contains(string(data), `"input_location":"query"`) {
+		t.Errorf("Expected input_location in JSON, got %s", data)
+	}
+}
+
 func TestGetTools(t *testing.T) {
 	provider := NewProvider("https://jira.example.com", "user", "pass")
 	tools := provider.GetTools()
@@ -41,13 +122,24 @@ func TestGetTools(t *testing.T) {
 
 	// Expected tools
 	expectedTools := map[string]bool{
-		"jira_search_issues":   false,
-		"jira_get_issue":       false,
-		"jira_create_issue":    false,
-		"jira_update_issue":    false,
-		"jira_get_projects":    false,
-		"jira_add_comment":     false,
-		"jira_get_user_issues": false,
+		"jira_search_issues":       false,
+		"jira_get_issue":           false,
+		"jira_create_issue":        false,
+		"jira_update_issue":        false,
+		"jira_get_transitions":     false,
+		"jira_do_transition":       false,
+		"jira_get_projects":        false,
+		"jira_add_comment":         false,
+		"jira_get_user_issues":     false,
+		"jira_list_filters":        false,
+		"jira_run_filter":          false,
+		"jira_get_attachments":     false,
+		"jira_get_issue_changelog": false,
+		"jira_list_watchers":       false,
+		"jira_add_watcher":         false,
+		"jira_list_boards":         false,
+		"jira_list_sprints":        false,
+		"jira_get_sprint_issues":   false,
 	}
 
 	// Check all expected tools are present
@@ -123,6 +215,126 @@ func TestJiraSearchIssuesTool(t *testing.T) {
 	}
 }
 
+func TestJiraSearchIssuesExamples(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	var searchTool *utcp.Tool
+	for _, tool := range provider.GetTools() {
+		if tool.Name == "jira_search_issues" {
+			searchTool = &tool
+			break
+		}
+	}
+
+	if searchTool == nil {
+		t.Fatal("jira_search_issues tool not found")
+	}
+
+	if len(searchTool.Examples) == 0 {
+		t.Fatal("Expected at least one example for jira_search_issues")
+	}
+
+	for _, example := range searchTool.Examples {
+		if example.Name == "" {
+			t.Error("Example has empty name")
+		}
+
+		jql, ok := example.Input["jql"].(string)
+		if !ok || jql == "" {
+			t.Errorf("Example '%s' is missing a JQL input", example.Name)
+		}
+
+		if example.Output == nil {
+			t.Errorf("Example '%s' is missing an output", example.Name)
+		}
+
+		for name := range example.Input {
+			if _, exists := searchTool.Inputs.Properties[name]; !exists {
+				t.Errorf("Example '%s' uses undeclared input %s", example.Name, name)
+			}
+		}
+	}
+}
+
+func TestJiraGetIssueChangelogTool(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	var changelogTool *utcp.Tool
+	for _, tool := range provider.GetTools() {
+		if tool.Name == "jira_get_issue_changelog" {
+			changelogTool = &tool
+			break
+		}
+	}
+
+	if changelogTool == nil {
+		t.Fatal("jira_get_issue_changelog tool not found")
+	}
+
+	expectedURL := "https://jira.example.com/rest/api/2/issue/${issueKey}/changelog"
+	if changelogTool.ToolProvider["url"] != expectedURL {
+		t.Errorf("Expected URL %s, got %v", expectedURL, changelogTool.ToolProvider["url"])
+	}
+
+	if len(changelogTool.Inputs.Required) != 1 || changelogTool.Inputs.Required[0] != "issueKey" {
+		t.Errorf("Expected 'issueKey' as only required field, got %v", changelogTool.Inputs.Required)
+	}
+
+	for _, name := range []string{"startAt", "maxResults"} {
+		if _, exists := changelogTool.Inputs.Properties[name]; !exists {
+			t.Errorf("Expected '%s' pagination property", name)
+		}
+	}
+}
+
+func TestJiraAgileTools(t *testing.T) {
+	// A trailing slash on the base URL must not produce a double slash
+	provider := NewProvider("https://jira.example.com/", "user", "pass")
+
+	expectedURLs := map[string]string{
+		"jira_list_boards":       "https://jira.example.com/rest/agile/1.0/board",
+		"jira_list_sprints":      "https://jira.example.com/rest/agile/1.0/board/${boardId}/sprint",
+		"jira_get_sprint_issues": "https://jira.example.com/rest/agile/1.0/sprint/${sprintId}/issue",
+	}
+
+	for _, tool := range provider.GetTools() {
+		expectedURL, exists := expectedURLs[tool.Name]
+		if !exists {
+			continue
+		}
+		delete(expectedURLs, tool.Name)
+
+		if tool.ToolProvider["url"] != expectedURL {
+			t.Errorf("Expected URL %s for %s, got %v", expectedURL, tool.Name, tool.ToolProvider["url"])
+		}
+
+		auth, _ := tool.ToolProvider["auth"].(map[string]interface{})
+		if auth["auth_type"] != "basic" {
+			t.Errorf("Expected basic auth for %s, got %v", tool.Name, auth["auth_type"])
+		}
+	}
+
+	for name := range expectedURLs {
+		t.Errorf("Expected tool not found: %s", name)
+	}
+}
+
+func TestJiraToolRetrySpecs(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	for _, tool := range provider.GetTools() {
+		method := tool.ToolProvider["http_method"]
+
+		if method == "GET" && (tool.Retry == nil || len(tool.Retry.RetryOn) == 0) {
+			t.Errorf("Expected retry spec on GET tool %s", tool.Name)
+		}
+
+		if method != "GET" && tool.Retry != nil {
+			t.Errorf("Expected no retry spec on %v tool %s", method, tool.Name)
+		}
+	}
+}
+
 func TestJiraGetIssueTool(t *testing.T) {
 	provider := NewProvider("https://jira.example.com", "user", "pass")
 	tools := provider.GetTools()
@@ -219,6 +431,11 @@ func TestJiraCreateIssueTool(t *testing.T) {
 		t.Errorf("Expected no default for priority, got %v", priorityProperty.Default)
 	}
 
+	// Create rejects undeclared inputs
+	if ap := createTool.Inputs.AdditionalProperties; ap == nil || *ap {
+		t.Errorf("Expected additionalProperties false, got %v", ap)
+	}
+
 	// Test HTTP method
 	providerConfig := createTool.ToolProvider
 
@@ -227,6 +444,119 @@ func TestJiraCreateIssueTool(t *testing.T) {
 	}
 }
 
+func TestJiraRunFilterTool(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+	tools := provider.GetTools()
+
+	var filterTool *utcp.Tool
+	for _, tool := range tools {
+		if tool.Name == "jira_run_filter" {
+			filterTool = &tool
+			break
+		}
+	}
+
+	if filterTool == nil {
+		t.Fatal("jira_run_filter tool not found")
+	}
+
+	if len(filterTool.Inputs.Required) != 1 || filterTool.Inputs.Required[0] != "filterId" {
+		t.Error("Expected 'filterId' to be the only required field")
+	}
+
+	url, ok := filterTool.ToolProvider["url"].(string)
+	if !ok {
+		t.Fatal("URL is not a string")
+	}
+
+	if !strings.Contains(url, "${filterId}") {
+		t.Errorf("Expected URL to contain ${filterId} placeholder, got %s", url)
+	}
+
+	if !strings.HasPrefix(url, "https://jira.example.com/rest/api/2/search") {
+		t.Errorf("Expected URL to use the search endpoint, got %s", url)
+	}
+}
+
+func TestJiraCreateIssueDueDateFormat(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	for _, tool := range provider.GetTools() {
+		if tool.Name != "jira_create_issue" {
+			continue
+		}
+
+		if format := tool.Inputs.Properties["duedate"].Format; format != "date" {
+			t.Errorf("Expected 'duedate' format 'date', got '%s'", format)
+		}
+		return
+	}
+
+	t.Fatal("jira_create_issue tool not found")
+}
+
+func TestJiraAddWatcherTool(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+
+	var watcherTool *utcp.Tool
+	for _, tool := range provider.GetTools() {
+		if tool.Name == "jira_add_watcher" {
+			watcherTool = &tool
+			break
+		}
+	}
+
+	if watcherTool == nil {
+		t.Fatal("jira_add_watcher tool not found")
+	}
+
+	if watcherTool.ToolProvider["http_method"] != "POST" {
+		t.Errorf("Expected http_method 'POST', got %v", watcherTool.ToolProvider["http_method"])
+	}
+
+	if len(watcherTool.Inputs.Required) != 1 || watcherTool.Inputs.Required[0] != "issueKey" {
+		t.Error("Expected 'issueKey' to be the only required field")
+	}
+
+	url, _ := watcherTool.ToolProvider["url"].(string)
+	if url != "https://jira.example.com/rest/api/2/issue/${issueKey}/watchers" {
+		t.Errorf("Unexpected URL: %s", url)
+	}
+
+	// Jira takes the username as a bare JSON string, not an object
+	if watcherTool.ToolProvider["body_field"] != "username" {
+		t.Errorf("Expected body_field 'username', got %v", watcherTool.ToolProvider["body_field"])
+	}
+}
+
+func TestToolDependencies(t *testing.T) {
+	provider := NewProvider("https://jira.example.com", "user", "pass")
+	tools := provider.GetTools()
+
+	byName := make(map[string]utcp.Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+
+	transition, ok := byName["jira_do_transition"]
+	if !ok {
+		t.Fatal("jira_do_transition tool not found")
+	}
+
+	if len(transition.DependsOn) != 1 || transition.DependsOn[0] != "jira_get_transitions" {
+		t.Errorf("Expected jira_do_transition to depend on jira_get_transitions, got %v", transition.DependsOn)
+	}
+
+	// Every declared dependency must be a tool the provider serves
+	for _, tool := range tools {
+		for _, dependency := range tool.DependsOn {
+			if _, exists := byName[dependency]; !exists {
+				t.Errorf("Tool %s depends on unknown tool %s", tool.Name, dependency)
+			}
+		}
+	}
+}
+
 func TestAllToolsHaveValidProviders(t *testing.T) {
 	provider := NewProvider("https://jira.example.com", "user", "pass")
 	tools := provider.GetTools()
@@ -249,11 +579,22 @@ func TestAllToolsHaveValidProviders(t *testing.T) {
 			t.Errorf("Tool %s has empty outputs type", tool.Name)
 		}
 
+		// Check enum defaults are valid options
+		for name, property := range tool.Inputs.Properties {
+			if err := property.Validate(); err != nil {
+				t.Errorf("Tool %s input %s: %v", tool.Name, name, err)
+			}
+		}
+
 		// Check provider configuration
 		if tool.ToolProvider == nil {
 			t.Errorf("Tool %s has nil ToolProvider", tool.Name)
 		}
 
+		if id := tool.ToolProvider["provider_id"]; id != tool.Name {
+			t.Errorf("Tool %s has mismatched provider_id %v", tool.Name, id)
+		}
+
 		providerType, ok := tool.ToolProvider["provider_type"].(string)
 		if !ok || providerType != "http" {
 			t.Errorf("Tool %s has invalid provider_type", tool.Name)
@@ -265,7 +606,7 @@ func TestAllToolsHaveValidProviders(t *testing.T) {
 		}
 
 		method, ok := tool.ToolProvider["http_method"].(string)
-		if !ok || (method != "GET" && method != "POST" && method != "PUT") {
+		if !ok || !utcp.ValidHTTPMethod(method) {
 			t.Errorf("Tool %s has invalid HTTP method: %s", tool.Name, method)
 		}
 
@@ -0,0 +1,184 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// IssueTemplatesKey is the provider option listing issue templates
+const IssueTemplatesKey = "issue_templates"
+
+// templateName matches valid issue template names
+var templateName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// IssueTemplate is a skeleton for a new issue. Each template is published as
+// its own create-issue tool whose inputs default to the template's values;
+// the server never fills templates in itself, so values are used as-is.
+type IssueTemplate struct {
+	Name        string
+	Summary     string
+	Description string
+	Fields      map[string]interface{}
+}
+
+// Validate checks that the template has a valid name and that summary and
+// description are not also set through fields
+func (t IssueTemplate) Validate() error {
+	if !templateName.MatchString(t.Name) {
+		return fmt.Errorf("issue template name %q must be lowercase letters, digits, '-' or '_'", t.Name)
+	}
+	if _, ok := t.Fields["summary"]; ok {
+		return fmt.Errorf("issue template %s sets summary in fields; use summary instead", t.Name)
+	}
+	if _, ok := t.Fields["description"]; ok {
+		return fmt.Errorf("issue template %s sets description in fields; use description instead", t.Name)
+	}
+	return nil
+}
+
+// ToolName returns the name of the create-issue tool for the template, e.g.
+// jira_create_access_request_issue for "access-request"
+func (t IssueTemplate) ToolName() string {
+	return "jira_create_" + strings.ReplaceAll(t.Name, "-", "_") + "_issue"
+}
+
+// Defaults returns the template's values keyed by Jira field name
+func (t IssueTemplate) Defaults() map[string]interface{} {
+	defaults := make(map[string]interface{}, len(t.Fields)+2)
+	for name, value := range t.Fields {
+		defaults[name] = value
+	}
+	if t.Summary != "" {
+		defaults["summary"] = t.Summary
+	}
+	if t.Description != "" {
+		defaults["description"] = t.Description
+	}
+	return defaults
+}
+
+// IssueTemplatesFromConfig reads and validates the issue_templates option,
+// a list of maps with name, summary, description and fields keys
+func IssueTemplatesFromConfig(config map[string]interface{}) ([]IssueTemplate, error) {
+	raw, exists := config[IssueTemplatesKey]
+	if !exists || raw == nil {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", IssueTemplatesKey)
+	}
+
+	templates := make([]IssueTemplate, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a map", IssueTemplatesKey, i)
+		}
+
+		template := IssueTemplate{}
+		template.Name, _ = entry["name"].(string)
+		template.Summary, _ = entry["summary"].(string)
+		template.Description, _ = entry["description"].(string)
+		if fields, exists := entry["fields"]; exists {
+			if template.Fields, ok = fields.(map[string]interface{}); !ok {
+				return nil, fmt.Errorf("%s[%d].fields must be a map", IssueTemplatesKey, i)
+			}
+		}
+
+		if err := template.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[template.Name] {
+			return nil, fmt.Errorf("duplicate issue template %s", template.Name)
+		}
+		seen[template.Name] = true
+
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// createFromTemplateTool describes creating an issue with the template's
+// values as input defaults. Inputs mirror jira_create_issue, plus any extra
+// fields the template sets; inputs with a default are no longer required.
+func (p *Provider) createFromTemplateTool(template IssueTemplate, base utcp.Tool) utcp.Tool {
+	defaults := template.Defaults()
+
+	properties := make(map[string]utcp.Property, len(base.Inputs.Properties)+len(defaults))
+	for name, property := range base.Inputs.Properties {
+		properties[name] = property
+	}
+
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, exists := properties[name]
+		if !exists {
+			property = utcp.Property{
+				Type:        jsonType(defaults[name]),
+				Description: fmt.Sprintf("Jira field %s", name),
+			}
+		}
+		property.Default = defaults[name]
+		properties[name] = property
+	}
+
+	var required []string
+	for _, name := range base.Inputs.Required {
+		if _, defaulted := defaults[name]; !defaulted {
+			required = append(required, name)
+		}
+	}
+
+	description := fmt.Sprintf("Create a Jira issue from the %s template. Inputs default to the template's values; override any of them as needed.", template.Name)
+	if len(names) > 0 {
+		description += " Template fields: " + strings.Join(names, ", ")
+	}
+
+	return utcp.Tool{
+		Name:        template.ToolName(),
+		Description: description,
+		Inputs: utcp.Schema{
+			Type:                 "object",
+			Properties:           properties,
+			Required:             required,
+			AdditionalProperties: utcp.Bool(false),
+		},
+		Outputs: base.Outputs,
+		Tags:    []string{"jira", "issue", "create", "template"},
+		ToolProvider: utcp.HTTPProvider(
+			template.ToolName(),
+			fmt.Sprintf("%s/rest/api/2/issue", p.BaseURL),
+			"POST",
+			p.auth(),
+		),
+	}
+}
+
+// jsonType returns the JSON Schema type of a config value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
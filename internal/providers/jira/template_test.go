@@ -0,0 +1,139 @@
+package jira
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func templatesConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name":     "jira",
+		"enabled":  true,
+		"base_url": "https://jira.example.com",
+		"username": "user",
+		"password": "pass",
+		"issue_templates": []interface{}{
+			map[string]interface{}{
+				"name":        "bug",
+				"description": "Steps to reproduce:",
+				"fields": map[string]interface{}{
+					"project":   map[string]interface{}{"key": "OPS"},
+					"issuetype": map[string]interface{}{"name": "Bug"},
+					"labels":    []interface{}{"triage"},
+					"components": []interface{}{
+						map[string]interface{}{"name": "backend"},
+					},
+				},
+			},
+			map[string]interface{}{
+				"name":    "access-request",
+				"summary": "Access request",
+				"fields": map[string]interface{}{
+					"project":   map[string]interface{}{"key": "IT"},
+					"issuetype": map[string]interface{}{"name": "Task"},
+				},
+			},
+		},
+	}
+}
+
+func TestCreateIssueFromTemplateTools(t *testing.T) {
+	provider, err := NewProviderFromConfig(templatesConfig())
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	byName := make(map[string]utcp.Tool)
+	for _, tool := range provider.GetTools() {
+		byName[tool.Name] = tool
+	}
+
+	bug, ok := byName["jira_create_bug_issue"]
+	if !ok {
+		t.Fatal("jira_create_bug_issue tool not found")
+	}
+	if _, ok := byName["jira_create_access_request_issue"]; !ok {
+		t.Fatal("jira_create_access_request_issue tool not found")
+	}
+
+	if err := bug.Validate(); err != nil {
+		t.Errorf("Expected valid tool, got %v", err)
+	}
+
+	if bug.ToolProvider["http_method"] != "POST" || bug.ToolProvider["url"] != "https://jira.example.com/rest/api/2/issue" {
+		t.Errorf("Expected POST to the create-issue endpoint, got %v %v", bug.ToolProvider["http_method"], bug.ToolProvider["url"])
+	}
+
+	project := bug.Inputs.Properties["project"]
+	if !reflect.DeepEqual(project.Default, map[string]interface{}{"key": "OPS"}) {
+		t.Errorf("Expected project default {key: OPS}, got %v", project.Default)
+	}
+	if project.Description != "Project key or ID" {
+		t.Errorf("Expected jira_create_issue's project description, got %q", project.Description)
+	}
+
+	if bug.Inputs.Properties["description"].Default != "Steps to reproduce:" {
+		t.Errorf("Expected description default, got %v", bug.Inputs.Properties["description"].Default)
+	}
+
+	components := bug.Inputs.Properties["components"]
+	if components.Type != "array" || components.Default == nil {
+		t.Errorf("Expected extra template field as an array input with a default, got %+v", components)
+	}
+
+	// Only the summary is left for the agent to supply
+	if strings.Join(bug.Inputs.Required, ",") != "summary" {
+		t.Errorf("Expected only summary to be required, got %v", bug.Inputs.Required)
+	}
+
+	if required := byName["jira_create_access_request_issue"].Inputs.Required; len(required) != 0 {
+		t.Errorf("Expected no required inputs when the template sets summary, got %v", required)
+	}
+
+	// The base tool is unchanged
+	if _, hasDefault := byName["jira_create_issue"].Inputs.Properties["project"]; !hasDefault {
+		t.Fatal("jira_create_issue lost its project input")
+	}
+	if byName["jira_create_issue"].Inputs.Properties["project"].Default != nil {
+		t.Error("Expected jira_create_issue inputs to keep no defaults")
+	}
+}
+
+func TestNoTemplateToolsWithoutTemplates(t *testing.T) {
+	for _, tool := range NewProvider("https://jira.example.com", "user", "pass").GetTools() {
+		for _, tag := range tool.Tags {
+			if tag == "template" {
+				t.Errorf("Unexpected template tool %s", tool.Name)
+			}
+		}
+	}
+}
+
+func TestIssueTemplatesFromConfigValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates interface{}
+	}{
+		{"not a list", "bug"},
+		{"entry not a map", []interface{}{"bug"}},
+		{"invalid name", []interface{}{map[string]interface{}{"name": "Bug Report"}}},
+		{"fields not a map", []interface{}{map[string]interface{}{"name": "bug", "fields": "x"}}},
+		{"summary in fields", []interface{}{map[string]interface{}{"name": "bug", "fields": map[string]interface{}{"summary": "x"}}}},
+		{"duplicate", []interface{}{
+			map[string]interface{}{"name": "bug"},
+			map[string]interface{}{"name": "bug"},
+		}},
+	}
+
+	for _, tt := range tests {
+		config := templatesConfig()
+		config[IssueTemplatesKey] = tt.templates
+
+		if _, err := NewProviderFromConfig(config); err == nil {
+			t.Errorf("%s: expected error, got nil", tt.name)
+		}
+	}
+}
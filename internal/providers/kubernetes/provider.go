@@ -0,0 +1,187 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rh-utcp/rh-utcp/internal/providers"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// Provider represents a read-only Kubernetes API server provider
+type Provider struct {
+	providers.BaseProvider
+	Token string
+}
+
+// NewProvider creates a new Kubernetes provider
+func NewProvider(baseURL, token string) *Provider {
+	return &Provider{
+		BaseProvider: providers.BaseProvider{
+			Type:    "kubernetes",
+			Enabled: true,
+			BaseURL: strings.TrimRight(baseURL, "/"),
+		},
+		Token: token,
+	}
+}
+
+// NewProviderFromConfig creates a new Kubernetes provider from configuration
+func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, error) {
+	name, _ := config["name"].(string)
+	baseURL, _ := config["base_url"].(string)
+	token, _ := config["token"].(string)
+	enabled, _ := config["enabled"].(bool)
+
+	if baseURL == "" {
+		return nil, fmt.Errorf("base_url is required")
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("token is required for Kubernetes provider")
+	}
+
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewProvider(baseURL, token)
+	provider.Name = name
+	provider.Enabled = enabled
+	provider.Headers = headers
+
+	return provider, nil
+}
+
+// RequiredEnv returns the environment variables referenced by Kubernetes tools
+func (p *Provider) RequiredEnv() []string {
+	return []string{"KUBE_TOKEN"}
+}
+
+// namespaceProperty is the required namespace input of namespaced resources
+var namespaceProperty = utcp.Property{
+	Type:        "string",
+	Description: "Namespace of the resource (e.g., 'default')",
+}
+
+// listProperties returns the common inputs of list tools
+func listProperties() map[string]utcp.Property {
+	return map[string]utcp.Property{
+		"namespace": namespaceProperty,
+		"labelSelector": {
+			Type:        "string",
+			Description: "Only return objects matching this label selector (e.g., 'app=web')",
+		},
+		"fieldSelector": {
+			Type:        "string",
+			Description: "Only return objects matching this field selector (e.g., 'status.phase=Running')",
+		},
+		"limit": {
+			Type:        "integer",
+			Description: "Maximum number of objects to return",
+			Default:     100,
+		},
+	}
+}
+
+// GetTools returns all available Kubernetes tools
+func (p *Provider) GetTools() []utcp.Tool {
+	tools := []utcp.Tool{}
+
+	// List pods tool
+	tools = append(tools, utcp.Tool{
+		Name:        "k8s_list_pods",
+		Description: "List pods in a namespace",
+		Inputs: utcp.Schema{
+			Type:       "object",
+			Properties: listProperties(),
+			Required:   []string{"namespace"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "PodList with metadata, spec, and status of each pod",
+		},
+		Tags: []string{"kubernetes", "pods", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"k8s_list_pods",
+			fmt.Sprintf("%s/api/v1/namespaces/${namespace}/pods", p.BaseURL),
+			"GET",
+			utcp.BearerAuth("KUBE_TOKEN"),
+		),
+	})
+
+	// Get pod tool
+	tools = append(tools, utcp.Tool{
+		Name:        "k8s_get_pod",
+		Description: "Get a pod by name",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"namespace": namespaceProperty,
+				"name": {
+					Type:        "string",
+					Description: "Pod name",
+				},
+			},
+			Required: []string{"namespace", "name"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Pod metadata, spec, and status including container states",
+		},
+		Tags: []string{"kubernetes", "pods", "get"},
+		ToolProvider: utcp.HTTPProvider(
+			"k8s_get_pod",
+			fmt.Sprintf("%s/api/v1/namespaces/${namespace}/pods/${name}", p.BaseURL),
+			"GET",
+			utcp.BearerAuth("KUBE_TOKEN"),
+		),
+	})
+
+	// List deployments tool
+	tools = append(tools, utcp.Tool{
+		Name:        "k8s_list_deployments",
+		Description: "List deployments in a namespace",
+		Inputs: utcp.Schema{
+			Type:       "object",
+			Properties: listProperties(),
+			Required:   []string{"namespace"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "DeploymentList with replica counts and rollout status",
+		},
+		Tags: []string{"kubernetes", "deployments", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"k8s_list_deployments",
+			fmt.Sprintf("%s/apis/apps/v1/namespaces/${namespace}/deployments", p.BaseURL),
+			"GET",
+			utcp.BearerAuth("KUBE_TOKEN"),
+		),
+	})
+
+	// Get events tool
+	tools = append(tools, utcp.Tool{
+		Name:        "k8s_get_events",
+		Description: "List events in a namespace, e.g. to find why a pod is failing",
+		Inputs: utcp.Schema{
+			Type:       "object",
+			Properties: listProperties(),
+			Required:   []string{"namespace"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "EventList with reason, message, involved object, and timestamps",
+		},
+		Tags: []string{"kubernetes", "events", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"k8s_get_events",
+			fmt.Sprintf("%s/api/v1/namespaces/${namespace}/events", p.BaseURL),
+			"GET",
+			utcp.BearerAuth("KUBE_TOKEN"),
+		),
+	})
+
+	return utcp.WithInputLocations(utcp.WithDefaultRetry(utcp.ApplyHeaders(tools, p.Headers)))
+}
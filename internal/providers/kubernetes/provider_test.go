@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func TestNewProviderFromConfig(t *testing.T) {
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"name":     "cluster",
+		"enabled":  true,
+		"base_url": "https://api.cluster.example.com:6443/",
+		"token":    "test-token",
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	if provider.GetName() != "cluster" || provider.GetType() != "kubernetes" {
+		t.Errorf("Expected cluster/kubernetes, got %s/%s", provider.GetName(), provider.GetType())
+	}
+
+	if _, err := NewProviderFromConfig(map[string]interface{}{"base_url": "https://k8s"}); err == nil {
+		t.Error("Expected error when token is missing")
+	}
+
+	if _, err := NewProviderFromConfig(map[string]interface{}{"token": "test-token"}); err == nil {
+		t.Error("Expected error when base_url is missing")
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"base_url":        "https://k8s.example.com",
+		"token":           "test-token",
+		"default_headers": map[string]interface{}{"X-Corp-Context": "engineering"},
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	for _, tool := range provider.GetTools() {
+		headers, _ := tool.ToolProvider["headers"].(map[string]string)
+		if headers["X-Corp-Context"] != "engineering" {
+			t.Errorf("Tool %s missing default header, got %v", tool.Name, tool.ToolProvider["headers"])
+		}
+	}
+
+	for _, tool := range NewProvider("https://k8s.example.com", "test-token").GetTools() {
+		if _, exists := tool.ToolProvider["headers"]; exists {
+			t.Errorf("Tool %s should have no headers by default", tool.Name)
+		}
+	}
+}
+
+func TestRequiredEnv(t *testing.T) {
+	provider := NewProvider("https://k8s.example.com", "test-token")
+
+	required := provider.RequiredEnv()
+	if len(required) != 1 || required[0] != "KUBE_TOKEN" {
+		t.Errorf("Expected [KUBE_TOKEN], got %v", required)
+	}
+}
+
+func TestGetTools(t *testing.T) {
+	provider := NewProvider("https://k8s.example.com/", "test-token")
+
+	expectedURLs := map[string]string{
+		"k8s_list_pods":        "https://k8s.example.com/api/v1/namespaces/${namespace}/pods",
+		"k8s_get_pod":          "https://k8s.example.com/api/v1/namespaces/${namespace}/pods/${name}",
+		"k8s_list_deployments": "https://k8s.example.com/apis/apps/v1/namespaces/${namespace}/deployments",
+		"k8s_get_events":       "https://k8s.example.com/api/v1/namespaces/${namespace}/events",
+	}
+
+	tools := provider.GetTools()
+	if len(tools) != len(expectedURLs) {
+		t.Errorf("Expected %d tools, got %d", len(expectedURLs), len(tools))
+	}
+
+	for _, tool := range tools {
+		expectedURL, exists := expectedURLs[tool.Name]
+		if !exists {
+			t.Errorf("Unexpected tool: %s", tool.Name)
+			continue
+		}
+
+		if tool.ToolProvider["url"] != expectedURL {
+			t.Errorf("Expected URL %s for %s, got %v", expectedURL, tool.Name, tool.ToolProvider["url"])
+		}
+
+		if tool.ToolProvider["http_method"] != "GET" {
+			t.Errorf("Expected read-only GET for %s, got %v", tool.Name, tool.ToolProvider["http_method"])
+		}
+
+		if tool.Inputs.Required[0] != "namespace" {
+			t.Errorf("Expected 'namespace' to be required for %s, got %v", tool.Name, tool.Inputs.Required)
+		}
+
+		auth, _ := tool.ToolProvider["auth"].(map[string]interface{})
+		if auth["header_value"] != "Bearer $KUBE_TOKEN" {
+			t.Errorf("Expected bearer token auth for %s, got %v", tool.Name, auth)
+		}
+	}
+}
+
+func TestAllToolsHaveValidProviders(t *testing.T) {
+	provider := NewProvider("https://k8s.example.com", "test-token")
+
+	for _, tool := range provider.GetTools() {
+		if err := tool.Validate(); err != nil {
+			t.Errorf("Tool %s is invalid: %v", tool.Name, err)
+		}
+
+		if tool.Description == "" {
+			t.Errorf("Tool %s has empty description", tool.Name)
+		}
+
+		if !hasTag(tool, "kubernetes") {
+			t.Errorf("Tool %s missing 'kubernetes' tag", tool.Name)
+		}
+	}
+}
+
+func hasTag(tool utcp.Tool, tag string) bool {
+	for _, t := range tool.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// PrefixedProvider wraps a Provider and namespaces its tools so that several
+// instances of the same provider type can be served side by side
+type PrefixedProvider struct {
+	Provider
+
+	prefix string
+}
+
+// NewPrefixedProvider wraps provider, prepending prefix to each tool name
+func NewPrefixedProvider(provider Provider, prefix string) *PrefixedProvider {
+	return &PrefixedProvider{
+		Provider: provider,
+		prefix:   prefix,
+	}
+}
+
+// Unwrap returns the underlying provider
+func (p *PrefixedProvider) Unwrap() Provider {
+	return p.Provider
+}
+
+// GetTools returns the underlying tools with prefixed names
+func (p *PrefixedProvider) GetTools() []utcp.Tool {
+	return p.applyPrefix(p.Provider.GetTools())
+}
+
+// GetToolsContext returns the underlying tools with prefixed names,
+// refreshing them through GetToolsContext when the provider is async
+func (p *PrefixedProvider) GetToolsContext(ctx context.Context) ([]utcp.Tool, error) {
+	async, ok := p.Provider.(AsyncProvider)
+	if !ok {
+		return p.GetTools(), nil
+	}
+
+	tools, err := async.GetToolsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.applyPrefix(tools), nil
+}
+
+// HealthCheck delegates to the underlying provider when it is checkable
+func (p *PrefixedProvider) HealthCheck(ctx context.Context) error {
+	if checker, ok := p.Provider.(HealthChecker); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// applyPrefix returns copies of tools with the prefix applied to each name
+// and added as a tag
+func (p *PrefixedProvider) applyPrefix(tools []utcp.Tool) []utcp.Tool {
+	tag := strings.TrimRight(p.prefix, "_-.")
+
+	prefixed := make([]utcp.Tool, len(tools))
+	for i, tool := range tools {
+		tool.Name = p.prefix + tool.Name
+		if tag != "" {
+			tool.Tags = append(append([]string(nil), tool.Tags...), tag)
+		}
+		prefixed[i] = tool
+	}
+
+	return prefixed
+}
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func TestPrefixedProvider(t *testing.T) {
+	mock := &MockProvider{
+		BaseProvider: BaseProvider{Name: "gitlab", Type: "gitlab", Enabled: true},
+		ToolsFunc: func() []utcp.Tool {
+			return []utcp.Tool{{Name: "gitlab_search_projects", Tags: []string{"gitlab"}}}
+		},
+	}
+
+	prefixed := NewPrefixedProvider(mock, "internal_")
+
+	tools := prefixed.GetTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+
+	if tools[0].Name != "internal_gitlab_search_projects" {
+		t.Errorf("Expected prefixed name 'internal_gitlab_search_projects', got %s", tools[0].Name)
+	}
+
+	if len(tools[0].Tags) != 2 || tools[0].Tags[1] != "internal" {
+		t.Errorf("Expected 'internal' tag to be added, got %v", tools[0].Tags)
+	}
+
+	// The underlying tools are left untouched
+	if mock.GetTools()[0].Name != "gitlab_search_projects" {
+		t.Error("Expected underlying tool name to be unchanged")
+	}
+
+	contextTools, err := prefixed.GetToolsContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetToolsContext failed: %v", err)
+	}
+	if contextTools[0].Name != "internal_gitlab_search_projects" {
+		t.Errorf("Expected prefixed name from GetToolsContext, got %s", contextTools[0].Name)
+	}
+}
+
+func TestRegistryToolPrefix(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterFactory("mock", func(config map[string]interface{}) (Provider, error) {
+		name, _ := config["name"].(string)
+		return &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Type: "mock", Enabled: true},
+			ToolsFunc: func() []utcp.Tool {
+				return []utcp.Tool{{Name: "mock_tool"}}
+			},
+		}, nil
+	})
+
+	registry.CreateProvider("internal", "mock", map[string]interface{}{"tool_prefix": "internal_"})
+	registry.CreateProvider("plain", "mock", map[string]interface{}{})
+
+	names := make(map[string]bool)
+	for _, tool := range registry.GetAllTools() {
+		names[tool.Name] = true
+	}
+
+	if !names["internal_mock_tool"] || !names["mock_tool"] {
+		t.Errorf("Expected 'internal_mock_tool' and 'mock_tool', got %v", names)
+	}
+}
+
+// checkedMockProvider is a mock provider with a failing health check
+type checkedMockProvider struct {
+	MockProvider
+}
+
+func (c *checkedMockProvider) HealthCheck(ctx context.Context) error {
+	return fmt.Errorf("upstream down")
+}
+
+func TestWrappersDelegateHealthCheck(t *testing.T) {
+	checked := &checkedMockProvider{}
+
+	wrappers := map[string]HealthChecker{
+		"prefixed": NewPrefixedProvider(checked, "x_"),
+		"cached":   NewCachedProvider(checked, time.Minute),
+	}
+
+	for name, wrapper := range wrappers {
+		if err := wrapper.HealthCheck(context.Background()); err == nil {
+			t.Errorf("%s: expected health check error to be delegated", name)
+		}
+	}
+}
@@ -1,9 +1,18 @@
 package providers
 
 import (
-	"fmt"
+	"context"
+	stderrors "errors"
+	"net"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+	"github.com/rh-utcp/rh-utcp/pkg/logger"
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
 )
 
@@ -20,24 +29,188 @@ type Provider interface {
 
 	// IsEnabled returns whether the provider is enabled
 	IsEnabled() bool
+
+	// SetEnabled enables or disables the provider at runtime
+	SetEnabled(enabled bool)
+
+	// RequiredEnv returns the environment variables referenced by the
+	// provider's tools that must be set for agents to call them
+	RequiredEnv() []string
+}
+
+// AsyncProvider is implemented by providers whose tools are fetched from an
+// upstream service (e.g. OpenAPI or GraphQL introspection) and may block
+type AsyncProvider interface {
+	Provider
+
+	// GetToolsContext refreshes and returns the provider's tools
+	GetToolsContext(ctx context.Context) ([]utcp.Tool, error)
+}
+
+// HealthChecker is implemented by providers that can verify their upstream
+// is reachable. Errors should be *errors.Error values from pkg/errors so the
+// health endpoint can report a status code and error type.
+type HealthChecker interface {
+	Provider
+
+	// HealthCheck returns nil when the provider's upstream is healthy
+	HealthCheck(ctx context.Context) error
 }
 
+// DefaultMaxConcurrentRefreshes is the default limit on simultaneous tool
+// collections across all providers
+const DefaultMaxConcurrentRefreshes = 4
+
+// DefaultCreateAttempts is the default number of times a provider factory is
+// called when it fails with a transient error
+const DefaultCreateAttempts = 3
+
+// DefaultHealthTimeout bounds a provider's health check when its config does
+// not set health_timeout
+const DefaultHealthTimeout = 5 * time.Second
+
+// createBackoff is the delay before the first factory retry, doubling after
+// each further failure
+var createBackoff = 200 * time.Millisecond
+
 // Factory is a function that creates a new provider instance
 type Factory func(config map[string]interface{}) (Provider, error)
 
-// Registry manages provider factories and instances
+// Registry manages provider factories and instances. Providers are held in
+// an immutable snapshot that writers copy and swap atomically, so lookups
+// and discovery never wait on provider creation or reloads.
 type Registry struct {
-	mu        sync.RWMutex
-	factories map[string]Factory
-	providers map[string]Provider
+	mu         sync.RWMutex
+	factories  map[string]Factory
+	state      atomic.Pointer[snapshot]
+	refreshSem chan struct{}
+	cacheTTL   time.Duration
+	maxTools   int
+	attempts   int
+}
+
+// snapshot is an immutable view of the registry's providers and their
+// per-provider state. It must not be modified once stored.
+type snapshot struct {
+	providers  map[string]Provider
+	breakers   map[string]*CircuitBreaker
+	health     map[string]*CircuitBreaker
+	identities map[string]string
+	timeouts   map[string]time.Duration
+}
+
+// newSnapshot returns an empty snapshot
+func newSnapshot() *snapshot {
+	return &snapshot{
+		providers:  make(map[string]Provider),
+		breakers:   make(map[string]*CircuitBreaker),
+		health:     make(map[string]*CircuitBreaker),
+		identities: make(map[string]string),
+		timeouts:   make(map[string]time.Duration),
+	}
+}
+
+// clone returns a copy of the snapshot that may be modified
+func (s *snapshot) clone() *snapshot {
+	next := &snapshot{
+		providers:  make(map[string]Provider, len(s.providers)+1),
+		breakers:   make(map[string]*CircuitBreaker, len(s.breakers)+1),
+		health:     make(map[string]*CircuitBreaker, len(s.health)+1),
+		identities: make(map[string]string, len(s.identities)+1),
+		timeouts:   make(map[string]time.Duration, len(s.timeouts)+1),
+	}
+	for name, provider := range s.providers {
+		next.providers[name] = provider
+	}
+	for name, breaker := range s.breakers {
+		next.breakers[name] = breaker
+	}
+	for name, breaker := range s.health {
+		next.health[name] = breaker
+	}
+	for name, identity := range s.identities {
+		next.identities[name] = identity
+	}
+	for name, timeout := range s.timeouts {
+		next.timeouts[name] = timeout
+	}
+	return next
 }
 
 // NewRegistry creates a new provider registry
 func NewRegistry() *Registry {
-	return &Registry{
-		factories: make(map[string]Factory),
-		providers: make(map[string]Provider),
+	r := &Registry{
+		factories:  make(map[string]Factory),
+		refreshSem: make(chan struct{}, DefaultMaxConcurrentRefreshes),
+		attempts:   DefaultCreateAttempts,
+	}
+	r.state.Store(newSnapshot())
+	return r
+}
+
+// snapshot returns the current providers without locking
+func (r *Registry) snapshot() *snapshot {
+	return r.state.Load()
+}
+
+// SetMaxConcurrentRefreshes limits how many providers GetAllTools calls, and
+// how many async providers GetAllToolsContext refreshes, at the same time.
+// Values below 1 are treated as 1.
+func (r *Registry) SetMaxConcurrentRefreshes(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshSem = make(chan struct{}, n)
+}
+
+// SetToolCacheTTL wraps providers created afterwards in a CachedProvider
+// that memoizes their tools for ttl. Zero disables caching.
+func (r *Registry) SetToolCacheTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cacheTTL = ttl
+}
+
+// SetCreateAttempts sets how many times CreateProvider calls a factory that
+// fails with a transient error. Values below 1 are treated as 1.
+func (r *Registry) SetCreateAttempts(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = n
+}
+
+// SetMaxTools caps how many tools GetAllTools and GetAllToolsContext return
+// so a misconfigured provider cannot grow the manual without bound. Zero
+// disables the cap.
+func (r *Registry) SetMaxTools(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxTools = n
+}
+
+// capTools truncates tools to the registry's cap, logging a warning when
+// tools are dropped
+func (r *Registry) capTools(tools []utcp.Tool) []utcp.Tool {
+	r.mu.RLock()
+	max := r.maxTools
+	r.mu.RUnlock()
+
+	if max <= 0 || len(tools) <= max {
+		return tools
 	}
+
+	logger.GetGlobal().WithFields(map[string]interface{}{
+		"tools":     len(tools),
+		"max_tools": max,
+	}).Warn("Truncating tools to the configured maximum")
+	return tools[:max]
 }
 
 // RegisterFactory registers a provider factory
@@ -46,54 +219,315 @@ func (r *Registry) RegisterFactory(providerType string, factory Factory) error {
 	defer r.mu.Unlock()
 
 	if _, exists := r.factories[providerType]; exists {
-		return fmt.Errorf("provider type %s already registered", providerType)
+		return errors.ConfigurationErrorf("provider type %s already registered", providerType).
+			WithContext("provider_type", providerType)
 	}
 
 	r.factories[providerType] = factory
 	return nil
 }
 
+// FactoryTypes returns the registered provider types in sorted order
+func (r *Registry) FactoryTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.factories))
+	for providerType := range r.factories {
+		types = append(types, providerType)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
 // CreateProvider creates a provider instance using the registered factory
 func (r *Registry) CreateProvider(name, providerType string, config map[string]interface{}) error {
 	r.mu.RLock()
 	factory, exists := r.factories[providerType]
+	cacheTTL := r.cacheTTL
+	attempts := r.attempts
 	r.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("unknown provider type: %s", providerType)
+		return errors.NotFoundError("provider type "+providerType).
+			WithContext("provider", name).
+			WithContext("provider_type", providerType)
 	}
 
 	// Add name to config
 	config["name"] = name
 
-	provider, err := factory(config)
+	provider, err := createWithRetry(factory, config, attempts)
+	if err != nil {
+		wrapped := errors.Wrapf(err, errors.ErrorTypeConfiguration, "failed to create provider %s", name).
+			WithContext("provider", name).
+			WithContext("provider_type", providerType)
+		return errors.WithOperation(errors.WithProvider(wrapped, name), "create")
+	}
+
+	provider, err = filterTools(provider, config)
 	if err != nil {
-		return fmt.Errorf("failed to create provider %s: %w", name, err)
+		wrapped := errors.Wrapf(err, errors.ErrorTypeConfiguration, "invalid tool filter for provider %s", name).
+			WithContext("provider", name).
+			WithContext("provider_type", providerType)
+		return errors.WithOperation(errors.WithProvider(wrapped, name), "create")
 	}
 
+	healthTimeout, err := durationOption(config, "health_timeout")
+	if err != nil {
+		wrapped := errors.Wrapf(err, errors.ErrorTypeConfiguration, "invalid health_timeout for provider %s", name).
+			WithContext("provider", name).
+			WithContext("provider_type", providerType)
+		return errors.WithOperation(errors.WithProvider(wrapped, name), "create")
+	}
+
+	if prefix, _ := config["tool_prefix"].(string); prefix != "" {
+		provider = NewPrefixedProvider(provider, prefix)
+	}
+
+	if cacheTTL > 0 {
+		provider = NewCachedProvider(provider, cacheTTL)
+	}
+
+	baseURL, _ := config["base_url"].(string)
 	r.mu.Lock()
-	r.providers[name] = provider
+	r.storeProvider(name, provider, providerIdentity(providerType, baseURL), healthTimeout)
 	r.mu.Unlock()
 
 	return nil
 }
 
-// GetProvider returns a provider by name
-func (r *Registry) GetProvider(name string) (Provider, bool) {
+// createWithRetry calls factory up to attempts times with exponential
+// backoff, retrying only transient failures. Configuration mistakes such as
+// a missing base_url fail immediately.
+func createWithRetry(factory Factory, config map[string]interface{}, attempts int) (Provider, error) {
+	backoff := createBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var provider Provider
+		provider, err = factory(config)
+		if err == nil {
+			return provider, nil
+		}
+
+		if !isTransient(err) || attempt == attempts {
+			break
+		}
+
+		logger.GetGlobal().WithError(err).WithFields(map[string]interface{}{
+			"provider": config["name"],
+			"attempt":  attempt,
+			"backoff":  backoff.String(),
+		}).Warn("Provider creation failed, retrying")
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return nil, err
+}
+
+// isTransient reports whether a factory error may succeed on retry: network,
+// timeout and upstream provider errors, context deadlines, and net.Error
+// failures such as the *url.Error returned by an HTTP client
+func isTransient(err error) bool {
+	var e *errors.Error
+	if stderrors.As(err, &e) {
+		switch e.Type {
+		case errors.ErrorTypeNetwork, errors.ErrorTypeTimeout, errors.ErrorTypeProvider:
+			return true
+		}
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) {
+		return true
+	}
+	return stderrors.Is(err, context.DeadlineExceeded)
+}
+
+// filterTools wraps provider in a FilteredProvider when its config sets
+// tools_allow or tools_deny. Patterns match tool names before any
+// tool_prefix is applied.
+func filterTools(provider Provider, config map[string]interface{}) (Provider, error) {
+	allow, err := stringList(config, "tools_allow")
+	if err != nil {
+		return nil, err
+	}
+	deny, err := stringList(config, "tools_deny")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(allow) == 0 && len(deny) == 0 {
+		return provider, nil
+	}
+	return NewFilteredProvider(provider, allow, deny)
+}
+
+// durationOption reads a positive duration such as "10s" from a provider
+// config value, returning zero when the key is unset
+func durationOption(config map[string]interface{}, key string) (time.Duration, error) {
+	var d time.Duration
+	switch value := config[key].(type) {
+	case nil:
+		return 0, nil
+	case time.Duration:
+		d = value
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.ValidationErrorf("%s must be a duration such as \"5s\": %v", key, err)
+		}
+		d = parsed
+	default:
+		return 0, errors.ValidationErrorf("%s must be a duration such as \"5s\"", key)
+	}
+
+	if d <= 0 {
+		return 0, errors.ValidationErrorf("%s must be positive", key)
+	}
+	return d, nil
+}
+
+// AddProvider registers an already constructed provider under its own name,
+// for programs that build providers in code rather than from configuration.
+// The provider is wrapped for tool caching like CreateProvider's are. It is
+// an error to add a provider without a name or with a name already in use.
+func (r *Registry) AddProvider(provider Provider) error {
+	name := provider.GetName()
+	if name == "" {
+		return errors.ValidationError("provider name is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.snapshot().providers[name]; exists {
+		return errors.ConfigurationErrorf("provider %s already registered", name).
+			WithContext("provider", name)
+	}
+
+	if r.cacheTTL > 0 {
+		provider = NewCachedProvider(provider, r.cacheTTL)
+	}
+
+	r.storeProvider(name, provider, providerIdentity(provider.GetType(), ""), 0)
+	return nil
+}
+
+// addProvider stores provider under name with fresh circuit breakers,
+// replacing any provider already registered with that name
+func (r *Registry) addProvider(name string, provider Provider, identity string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.storeProvider(name, provider, identity, 0)
+}
+
+// storeProvider swaps in a snapshot holding provider. A zero healthTimeout
+// uses DefaultHealthTimeout. r.mu must be held.
+func (r *Registry) storeProvider(name string, provider Provider, identity string, healthTimeout time.Duration) {
+	next := r.snapshot().clone()
+	next.providers[name] = provider
+	next.breakers[name] = NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+	next.health[name] = NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+	next.identities[name] = identity
+	if healthTimeout > 0 {
+		next.timeouts[name] = healthTimeout
+	} else {
+		delete(next.timeouts, name)
+	}
+	r.state.Store(next)
+}
+
+// providerIdentity identifies a provider's upstream across reloads
+func providerIdentity(providerType, baseURL string) string {
+	return providerType + "|" + baseURL
+}
+
+// Breaker returns the circuit breaker for a provider by name
+func (r *Registry) Breaker(name string) (*CircuitBreaker, bool) {
+	breaker, exists := r.snapshot().breakers[name]
+	return breaker, exists
+}
+
+// HealthBreaker returns the circuit breaker guarding a provider's health
+// checks by name
+func (r *Registry) HealthBreaker(name string) (*CircuitBreaker, bool) {
+	breaker, exists := r.snapshot().health[name]
+	return breaker, exists
+}
+
+// HealthTimeout returns how long a provider's health check may run before it
+// is reported as timed out
+func (r *Registry) HealthTimeout(name string) time.Duration {
+	if timeout, ok := r.snapshot().timeouts[name]; ok {
+		return timeout
+	}
+	return DefaultHealthTimeout
+}
+
+// Reload rebuilds the registry's providers using build, which is given an
+// empty registry sharing this registry's factories. Providers whose name,
+// type and base URL are unchanged keep their circuit-breaker state so that a
+// reload does not trigger a fresh round of probes against known-bad
+// upstreams. If build fails the current providers are left in place.
+func (r *Registry) Reload(build func(*Registry) error) error {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
+	next := &Registry{
+		factories:  make(map[string]Factory, len(r.factories)),
+		refreshSem: r.refreshSem,
+		cacheTTL:   r.cacheTTL,
+		attempts:   r.attempts,
+	}
+	for providerType, factory := range r.factories {
+		next.factories[providerType] = factory
+	}
+	r.mu.RUnlock()
+	next.state.Store(newSnapshot())
+
+	if err := build(next); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.snapshot()
+	built := next.snapshot().clone()
+
+	// Transfer state for providers that are unchanged across the reload
+	for name, identity := range built.identities {
+		if current.identities[name] == identity {
+			if breaker, ok := current.breakers[name]; ok {
+				built.breakers[name] = breaker
+			}
+			if breaker, ok := current.health[name]; ok {
+				built.health[name] = breaker
+			}
+		}
+	}
+
+	r.state.Store(built)
 
-	provider, exists := r.providers[name]
+	return nil
+}
+
+// GetProvider returns a provider by name
+func (r *Registry) GetProvider(name string) (Provider, bool) {
+	provider, exists := r.snapshot().providers[name]
 	return provider, exists
 }
 
 // GetAllProviders returns all registered providers
 func (r *Registry) GetAllProviders() []Provider {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	current := r.snapshot()
 
-	providers := make([]Provider, 0, len(r.providers))
-	for _, provider := range r.providers {
+	providers := make([]Provider, 0, len(current.providers))
+	for _, provider := range current.providers {
 		providers = append(providers, provider)
 	}
 
@@ -102,11 +536,8 @@ func (r *Registry) GetAllProviders() []Provider {
 
 // GetEnabledProviders returns only enabled providers
 func (r *Registry) GetEnabledProviders() []Provider {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
 	providers := make([]Provider, 0)
-	for _, provider := range r.providers {
+	for _, provider := range r.snapshot().providers {
 		if provider.IsEnabled() {
 			providers = append(providers, provider)
 		}
@@ -115,16 +546,210 @@ func (r *Registry) GetEnabledProviders() []Provider {
 	return providers
 }
 
-// GetAllTools returns all tools from all enabled providers
+// ProviderTagPrefix prefixes the tag identifying the provider a tool came from
+const ProviderTagPrefix = "provider:"
+
+// GetAllTools returns all tools from all enabled providers, each tagged with
+// its provider name. Providers are called concurrently within the refresh
+// limit and their tools are returned in provider name order. Duplicate names
+// within a provider are dropped with a warning; use GetAllToolsContext to
+// have them returned as errors.
 func (r *Registry) GetAllTools() []utcp.Tool {
-	providers := r.GetEnabledProviders()
+	providers := sortByName(r.GetEnabledProviders())
+
+	r.mu.RLock()
+	sem := r.refreshSem
+	r.mu.RUnlock()
+
+	results := make([][]utcp.Tool, len(providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			tools, err := safeGetTools(provider)
+			if err != nil {
+				return
+			}
+			unique, err := validateTools(provider.GetName(), tools)
+			if err != nil {
+				logger.GetGlobal().WithError(err).Warn("Dropped duplicate tools")
+			}
+			results[i] = withProviderTag(provider.GetName(), unique)
+		}(i, provider)
+	}
+	wg.Wait()
 
 	var tools []utcp.Tool
-	for _, provider := range providers {
-		tools = append(tools, provider.GetTools()...)
+	for _, result := range results {
+		tools = append(tools, result...)
 	}
 
-	return tools
+	return r.capTools(tools)
+}
+
+// sortByName sorts providers by name so collected tools come out in a
+// stable order
+func sortByName(providers []Provider) []Provider {
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].GetName() < providers[j].GetName()
+	})
+	return providers
+}
+
+// safeGetTools calls the provider's GetTools, recovering a panic into an
+// error so one buggy provider cannot take down tool discovery. The panic is
+// logged with the provider name.
+func safeGetTools(provider Provider) (tools []utcp.Tool, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			tools = nil
+			err = toolsError(provider.GetName(), errors.InternalErrorf("panic: %v", rec))
+			logger.GetGlobal().WithError(err).Error("Provider panicked in GetTools")
+		}
+	}()
+
+	return provider.GetTools(), nil
+}
+
+// validateTools returns tools without later repeats of a name, along with an
+// error identifying the provider and each repeated tool
+func validateTools(name string, tools []utcp.Tool) ([]utcp.Tool, error) {
+	seen := make(map[string]bool, len(tools))
+	unique := make([]utcp.Tool, 0, len(tools))
+
+	var errs []error
+	for _, tool := range tools {
+		if seen[tool.Name] {
+			err := errors.New(errors.ErrorTypeProvider, "duplicate tool name "+tool.Name).
+				WithContext("tool", tool.Name)
+			errs = append(errs, toolsError(name, err))
+			continue
+		}
+		seen[tool.Name] = true
+		unique = append(unique, tool)
+	}
+
+	return unique, stderrors.Join(errs...)
+}
+
+// withProviderTag returns copies of tools with a provider:<name> tag added.
+// Tag slices are copied so the provider's own tools are not modified.
+func withProviderTag(name string, tools []utcp.Tool) []utcp.Tool {
+	tag := ProviderTagPrefix + name
+
+	tagged := make([]utcp.Tool, len(tools))
+	for i, tool := range tools {
+		tool.Tags = append(append(make([]string, 0, len(tool.Tags)+1), tool.Tags...), tag)
+		tagged[i] = tool
+	}
+
+	return tagged
+}
+
+// GetAllToolsContext returns all tools from all enabled providers, calling
+// them concurrently while respecting the refresh limit. Tools are returned in
+// provider name order; tools from providers that fail are omitted and their
+// errors are joined in the result.
+func (r *Registry) GetAllToolsContext(ctx context.Context) ([]utcp.Tool, error) {
+	tools, _, err := r.GetAllToolsWithTotal(ctx)
+	return tools, err
+}
+
+// GetAllToolsWithTotal is GetAllToolsContext that also reports how many tools
+// the providers returned before the SetMaxTools cap was applied
+func (r *Registry) GetAllToolsWithTotal(ctx context.Context) ([]utcp.Tool, int, error) {
+	providers := sortByName(r.GetEnabledProviders())
+
+	r.mu.RLock()
+	sem := r.refreshSem
+	r.mu.RUnlock()
+
+	results := make([][]utcp.Tool, len(providers))
+	errs := make([]error, len(providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = toolsError(provider.GetName(), ctx.Err())
+				return
+			}
+
+			results[i], errs[i] = r.providerTools(ctx, provider)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var tools []utcp.Tool
+	for _, result := range results {
+		tools = append(tools, result...)
+	}
+
+	return r.capTools(tools), len(tools), stderrors.Join(errs...)
+}
+
+// providerTools returns one provider's tools tagged with its name. Async
+// providers are refreshed with ctx behind their circuit breaker.
+func (r *Registry) providerTools(ctx context.Context, provider Provider) (tools []utcp.Tool, err error) {
+	name := provider.GetName()
+
+	async, ok := provider.(AsyncProvider)
+	if !ok {
+		tools, err := safeGetTools(provider)
+		if err != nil {
+			return nil, err
+		}
+		tools, err = validateTools(name, tools)
+		return withProviderTag(name, tools), err
+	}
+
+	// A panic counts as a failed refresh so a half-open probe reopens the
+	// breaker instead of leaving it stuck
+	breaker, _ := r.Breaker(name)
+	defer func() {
+		if rec := recover(); rec != nil {
+			if breaker != nil {
+				breaker.RecordFailure()
+			}
+			tools, err = nil, toolsError(name, errors.InternalErrorf("panic: %v", rec))
+		}
+	}()
+
+	if breaker != nil && !breaker.Allow() {
+		return nil, toolsError(name, errors.New(errors.ErrorTypeProvider, "circuit breaker open"))
+	}
+
+	tools, err = async.GetToolsContext(ctx)
+	if err != nil {
+		if breaker != nil {
+			breaker.RecordFailure()
+		}
+		return nil, toolsError(name, err)
+	}
+	if breaker != nil {
+		breaker.RecordSuccess()
+	}
+
+	tools, err = validateTools(name, tools)
+	return withProviderTag(name, tools), err
+}
+
+// toolsError annotates a failed tool refresh with the provider and operation
+func toolsError(name string, err error) *errors.Error {
+	wrapped := errors.Wrapf(err, errors.ErrorTypeProvider, "provider %s", name)
+	return errors.WithOperation(errors.WithProvider(wrapped, name), "get_tools")
 }
 
 // Clear removes all providers from the registry
@@ -132,7 +757,150 @@ func (r *Registry) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.providers = make(map[string]Provider)
+	r.state.Store(newSnapshot())
+}
+
+// SetProviderEnabled enables or disables a provider by name
+func (r *Registry) SetProviderEnabled(name string, enabled bool) error {
+	provider, exists := r.GetProvider(name)
+	if !exists {
+		return errors.NotFoundError("provider "+name).WithContext("provider", name)
+	}
+
+	provider.SetEnabled(enabled)
+	return nil
+}
+
+// MissingEnv returns the environment variables required by the enabled
+// providers that are not set, keyed by provider name
+func (r *Registry) MissingEnv() map[string][]string {
+	missing := make(map[string][]string)
+
+	for _, provider := range r.GetEnabledProviders() {
+		for _, name := range provider.RequiredEnv() {
+			if _, ok := os.LookupEnv(name); !ok {
+				missing[provider.GetName()] = append(missing[provider.GetName()], name)
+			}
+		}
+	}
+
+	return missing
+}
+
+// DefaultHeadersKey is the config key holding headers added to every tool
+const DefaultHeadersKey = "default_headers"
+
+// HeadersFromConfig reads the optional default_headers map from a provider
+// config. Values must be strings.
+func HeadersFromConfig(config map[string]interface{}) (map[string]string, error) {
+	switch raw := config[DefaultHeadersKey].(type) {
+	case nil:
+		return nil, nil
+	case map[string]string:
+		return raw, nil
+	case map[string]interface{}:
+		headers := make(map[string]string, len(raw))
+		for name, value := range raw {
+			s, ok := value.(string)
+			if !ok {
+				return nil, errors.ValidationErrorf("%s value for %s must be a string", DefaultHeadersKey, name)
+			}
+			headers[name] = s
+		}
+		return headers, nil
+	default:
+		return nil, errors.ValidationErrorf("%s must be a map of header names to values", DefaultHeadersKey)
+	}
+}
+
+// AuthTypeKey is the config key holding the provider's configured auth type
+const AuthTypeKey = "auth_type"
+
+// OAuth2Env names the environment variables agents read for a provider's
+// OAuth2 client credentials
+type OAuth2Env struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+}
+
+// Names returns the environment variable names in a stable order
+func (e OAuth2Env) Names() []string {
+	return []string{e.ClientID, e.ClientSecret, e.TokenURL}
+}
+
+// Auth returns the tool provider auth block for the credentials
+func (e OAuth2Env) Auth() map[string]interface{} {
+	return utcp.OAuth2Auth(e.ClientID, e.ClientSecret, e.TokenURL)
+}
+
+// OAuth2EnvFromConfig returns the OAuth2 environment variable names for a
+// provider whose auth_type is oauth2. Names default to <prefix>_CLIENT_ID,
+// <prefix>_CLIENT_SECRET and <prefix>_TOKEN_URL and can be overridden with
+// the client_id_env, client_secret_env and token_url_env keys. ok is false
+// for any other auth type.
+func OAuth2EnvFromConfig(config map[string]interface{}, prefix string) (env OAuth2Env, ok bool) {
+	if authType, _ := config[AuthTypeKey].(string); authType != "oauth2" {
+		return OAuth2Env{}, false
+	}
+
+	env = OAuth2Env{
+		ClientID:     prefix + "_CLIENT_ID",
+		ClientSecret: prefix + "_CLIENT_SECRET",
+		TokenURL:     prefix + "_TOKEN_URL",
+	}
+	if name, _ := config["client_id_env"].(string); name != "" {
+		env.ClientID = name
+	}
+	if name, _ := config["client_secret_env"].(string); name != "" {
+		env.ClientSecret = name
+	}
+	if name, _ := config["token_url_env"].(string); name != "" {
+		env.TokenURL = name
+	}
+
+	return env, true
+}
+
+// EnvFromToolAuth returns the environment variables referenced by the auth
+// and auth_options blocks of tools, i.e. the "$NAME" values, sorted and
+// without repeats
+func EnvFromToolAuth(tools []utcp.Tool) []string {
+	seen := make(map[string]bool)
+	collect := func(auth map[string]interface{}) {
+		for _, value := range auth {
+			if s, ok := value.(string); ok && strings.HasPrefix(s, "$") {
+				seen[strings.TrimPrefix(s, "$")] = true
+			}
+		}
+	}
+
+	for _, tool := range tools {
+		auth, _ := tool.ToolProvider["auth"].(map[string]interface{})
+		collect(auth)
+
+		// auth_options is []map[string]interface{} when built with
+		// HTTPProviderMultiAuth and []interface{} when decoded from JSON
+		switch options := tool.ToolProvider["auth_options"].(type) {
+		case []map[string]interface{}:
+			for _, option := range options {
+				collect(option)
+			}
+		case []interface{}:
+			for _, option := range options {
+				option, _ := option.(map[string]interface{})
+				collect(option)
+			}
+		}
+	}
+
+	env := make([]string, 0, len(seen))
+	for name := range seen {
+		env = append(env, name)
+	}
+	sort.Strings(env)
+
+	return env
 }
 
 // BaseProvider provides common functionality for all providers
@@ -141,6 +909,9 @@ type BaseProvider struct {
 	Type    string
 	Enabled bool
 	BaseURL string
+	Headers map[string]string
+
+	mu sync.RWMutex
 }
 
 // GetName returns the provider name
@@ -155,5 +926,19 @@ func (b *BaseProvider) GetType() string {
 
 // IsEnabled returns whether the provider is enabled
 func (b *BaseProvider) IsEnabled() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	return b.Enabled
 }
+
+// SetEnabled enables or disables the provider
+func (b *BaseProvider) SetEnabled(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Enabled = enabled
+}
+
+// RequiredEnv returns no environment variables by default
+func (b *BaseProvider) RequiredEnv() []string {
+	return nil
+}
@@ -1,9 +1,21 @@
 package providers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/rh-utcp/rh-utcp/pkg/circuit"
+	"github.com/rh-utcp/rh-utcp/pkg/errors"
+	"github.com/rh-utcp/rh-utcp/pkg/logger"
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
 )
 
@@ -31,7 +43,7 @@ func TestNewRegistry(t *testing.T) {
 		t.Error("factories map is nil")
 	}
 
-	if registry.providers == nil {
+	if registry.snapshot().providers == nil {
 		t.Error("providers map is nil")
 	}
 }
@@ -62,6 +74,25 @@ func TestRegisterFactory(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for duplicate registration, got nil")
 	}
+
+	if !errors.Is(err, errors.ErrorTypeConfiguration) {
+		t.Errorf("Expected configuration error, got %v", err)
+	}
+}
+
+func TestFactoryTypes(t *testing.T) {
+	registry := NewRegistry()
+
+	factory := func(config map[string]interface{}) (Provider, error) {
+		return &MockProvider{}, nil
+	}
+	registry.RegisterFactory("wiki", factory)
+	registry.RegisterFactory("jira", factory)
+
+	types := registry.FactoryTypes()
+	if len(types) != 2 || types[0] != "jira" || types[1] != "wiki" {
+		t.Errorf("Expected [jira wiki], got %v", types)
+	}
 }
 
 func TestCreateProvider(t *testing.T) {
@@ -103,7 +134,53 @@ func TestCreateProvider(t *testing.T) {
 	// Test creating provider with unknown type
 	err = registry.CreateProvider("unknown", "unknown-type", map[string]interface{}{})
 	if err == nil {
-		t.Error("Expected error for unknown provider type, got nil")
+		t.Fatal("Expected error for unknown provider type, got nil")
+	}
+
+	if !errors.Is(err, errors.ErrorTypeNotFound) {
+		t.Errorf("Expected not_found error, got %v", err)
+	}
+
+	typedErr := err.(*errors.Error)
+	if typedErr.Context["provider"] != "unknown" || typedErr.Context["provider_type"] != "unknown-type" {
+		t.Errorf("Expected provider context, got %v", typedErr.Context)
+	}
+}
+
+func TestCreateProviderFactoryError(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterFactory("broken", func(config map[string]interface{}) (Provider, error) {
+		return nil, fmt.Errorf("base_url is required")
+	})
+
+	err := registry.CreateProvider("broken-provider", "broken", map[string]interface{}{})
+	if err == nil {
+		t.Fatal("Expected error from failing factory, got nil")
+	}
+
+	if !errors.Is(err, errors.ErrorTypeConfiguration) {
+		t.Errorf("Expected configuration error, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "base_url is required") {
+		t.Errorf("Expected factory error to be preserved, got %v", err)
+	}
+
+	if _, exists := registry.GetProvider("broken-provider"); exists {
+		t.Error("Expected failed provider not to be registered")
+	}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		t.Fatalf("Expected *errors.Error, got %T", err)
+	}
+
+	if e.Provider != "broken-provider" {
+		t.Errorf("Expected provider 'broken-provider', got '%s'", e.Provider)
+	}
+
+	if e.Operation != "create" {
+		t.Errorf("Expected operation 'create', got '%s'", e.Operation)
 	}
 }
 
@@ -119,7 +196,7 @@ func TestGetProvider(t *testing.T) {
 		},
 	}
 
-	registry.providers["test"] = mockProvider
+	registry.addProvider("test", mockProvider, "")
 
 	// Test getting existing provider
 	provider, exists := registry.GetProvider("test")
@@ -149,8 +226,8 @@ func TestGetAllProviders(t *testing.T) {
 		BaseProvider: BaseProvider{Name: "p2", Type: "mock", Enabled: false},
 	}
 
-	registry.providers["p1"] = provider1
-	registry.providers["p2"] = provider2
+	registry.addProvider("p1", provider1, "")
+	registry.addProvider("p2", provider2, "")
 
 	providers := registry.GetAllProviders()
 
@@ -188,9 +265,9 @@ func TestGetEnabledProviders(t *testing.T) {
 		BaseProvider: BaseProvider{Name: "enabled2", Type: "mock", Enabled: true},
 	}
 
-	registry.providers["enabled1"] = provider1
-	registry.providers["disabled"] = provider2
-	registry.providers["enabled2"] = provider3
+	registry.addProvider("enabled1", provider1, "")
+	registry.addProvider("disabled", provider2, "")
+	registry.addProvider("enabled2", provider3, "")
 
 	providers := registry.GetEnabledProviders()
 
@@ -244,9 +321,9 @@ func TestGetAllTools(t *testing.T) {
 		},
 	}
 
-	registry.providers["p1"] = provider1
-	registry.providers["p2"] = provider2
-	registry.providers["p3"] = provider3
+	registry.addProvider("p1", provider1, "")
+	registry.addProvider("p2", provider2, "")
+	registry.addProvider("p3", provider3, "")
 
 	allTools := registry.GetAllTools()
 
@@ -277,26 +354,575 @@ func TestGetAllTools(t *testing.T) {
 	}
 }
 
+func TestGetAllToolsProviderTags(t *testing.T) {
+	registry := NewRegistry()
+
+	jiraTools := []utcp.Tool{
+		{Name: "jira_search", Tags: make([]string, 1, 4)},
+	}
+	jiraTools[0].Tags[0] = "issues"
+
+	registry.addProvider("jira", &MockProvider{
+		BaseProvider: BaseProvider{Name: "jira", Type: "mock", Enabled: true},
+		ToolsFunc:    func() []utcp.Tool { return jiraTools },
+	}, "")
+	registry.addProvider("gitlab", &MockProvider{
+		BaseProvider: BaseProvider{Name: "gitlab", Type: "mock", Enabled: true},
+		ToolsFunc:    func() []utcp.Tool { return []utcp.Tool{{Name: "gitlab_list_projects"}} },
+	}, "")
+
+	expected := map[string][]string{
+		"jira_search":          {"issues", "provider:jira"},
+		"gitlab_list_projects": {"provider:gitlab"},
+	}
+
+	allTools := registry.GetAllTools()
+	if len(allTools) != len(expected) {
+		t.Fatalf("Expected %d tools, got %d", len(expected), len(allTools))
+	}
+
+	for _, tool := range allTools {
+		want := expected[tool.Name]
+		if strings.Join(tool.Tags, ",") != strings.Join(want, ",") {
+			t.Errorf("Expected tags %v for %s, got %v", want, tool.Name, tool.Tags)
+		}
+	}
+
+	// The provider's own tool must not gain the tag, even through spare capacity
+	if len(jiraTools[0].Tags) != 1 || jiraTools[0].Tags[:2][1] != "" {
+		t.Errorf("Expected provider tool tags to be unchanged, got %v", jiraTools[0].Tags[:2])
+	}
+}
+
+// EnvProvider is a mock provider that declares required environment variables
+type EnvProvider struct {
+	MockProvider
+	Env []string
+}
+
+func (e *EnvProvider) RequiredEnv() []string {
+	return e.Env
+}
+
+func TestMissingEnv(t *testing.T) {
+	t.Setenv("RHUTCP_TEST_PRESENT", "value")
+
+	registry := NewRegistry()
+	registry.addProvider("p1", &EnvProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "p1", Enabled: true}},
+		Env:          []string{"RHUTCP_TEST_PRESENT", "RHUTCP_TEST_MISSING"},
+	}, "")
+	registry.addProvider("p2", &EnvProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "p2", Enabled: true}},
+		Env:          []string{"RHUTCP_TEST_PRESENT"},
+	}, "")
+	registry.addProvider("disabled", &EnvProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "disabled", Enabled: false}},
+		Env:          []string{"RHUTCP_TEST_MISSING"},
+	}, "")
+
+	missing := registry.MissingEnv()
+
+	if len(missing) != 1 {
+		t.Fatalf("Expected 1 provider with missing env, got %d", len(missing))
+	}
+
+	if len(missing["p1"]) != 1 || missing["p1"][0] != "RHUTCP_TEST_MISSING" {
+		t.Errorf("Expected p1 to be missing RHUTCP_TEST_MISSING, got %v", missing["p1"])
+	}
+
+	if _, exists := missing["disabled"]; exists {
+		t.Error("Disabled provider should not be checked")
+	}
+}
+
+// SlowAsyncProvider is a mock async provider that tracks in-flight refreshes
+type SlowAsyncProvider struct {
+	MockProvider
+	Delay    time.Duration
+	InFlight *int32
+	MaxSeen  *int32
+	Err      error
+}
+
+func (s *SlowAsyncProvider) GetToolsContext(ctx context.Context) ([]utcp.Tool, error) {
+	current := atomic.AddInt32(s.InFlight, 1)
+	defer atomic.AddInt32(s.InFlight, -1)
+
+	for {
+		max := atomic.LoadInt32(s.MaxSeen)
+		if current <= max || atomic.CompareAndSwapInt32(s.MaxSeen, max, current) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(s.Delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	return []utcp.Tool{{Name: s.Name + "_tool"}}, nil
+}
+
+func TestGetAllToolsContextConcurrencyLimit(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetMaxConcurrentRefreshes(2)
+
+	var inFlight, maxSeen int32
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("async-%d", i)
+		registry.addProvider(name, &SlowAsyncProvider{
+			MockProvider: MockProvider{BaseProvider: BaseProvider{Name: name, Enabled: true}},
+			Delay:        20 * time.Millisecond,
+			InFlight:     &inFlight,
+			MaxSeen:      &maxSeen,
+		}, "")
+	}
+
+	tools, err := registry.GetAllToolsContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllToolsContext failed: %v", err)
+	}
+
+	if len(tools) != 6 {
+		t.Errorf("Expected 6 tools, got %d", len(tools))
+	}
+
+	if max := atomic.LoadInt32(&maxSeen); max > 2 {
+		t.Errorf("Expected at most 2 concurrent refreshes, saw %d", max)
+	}
+
+	if max := atomic.LoadInt32(&maxSeen); max < 2 {
+		t.Errorf("Expected refreshes to run concurrently, saw %d", max)
+	}
+}
+
+func TestGetAllToolsContextPartialFailure(t *testing.T) {
+	registry := NewRegistry()
+
+	var inFlight, maxSeen int32
+	registry.addProvider("sync", &MockProvider{
+		BaseProvider: BaseProvider{Name: "sync", Enabled: true},
+		ToolsFunc: func() []utcp.Tool {
+			return []utcp.Tool{{Name: "sync_tool"}}
+		},
+	}, "")
+	registry.addProvider("failing", &SlowAsyncProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "failing", Enabled: true}},
+		InFlight:     &inFlight,
+		MaxSeen:      &maxSeen,
+		Err:          fmt.Errorf("upstream unavailable"),
+	}, "")
+
+	tools, err := registry.GetAllToolsContext(context.Background())
+	if err == nil {
+		t.Error("Expected error from failing provider, got nil")
+	}
+
+	if len(tools) != 1 || tools[0].Name != "sync_tool" {
+		t.Errorf("Expected only sync_tool, got %v", tools)
+	}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		t.Fatalf("Expected *errors.Error, got %T", err)
+	}
+
+	if e.Provider != "failing" {
+		t.Errorf("Expected provider 'failing', got '%s'", e.Provider)
+	}
+
+	if e.Operation != "get_tools" {
+		t.Errorf("Expected operation 'get_tools', got '%s'", e.Operation)
+	}
+}
+
+func TestGetAllToolsDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	registry.addProvider("copy-paste", &MockProvider{
+		BaseProvider: BaseProvider{Name: "copy-paste", Enabled: true},
+		ToolsFunc: func() []utcp.Tool {
+			return []utcp.Tool{
+				{Name: "dup", Description: "first"},
+				{Name: "other"},
+				{Name: "dup", Description: "second"},
+			}
+		},
+	}, "")
+
+	tools, err := registry.GetAllToolsContext(context.Background())
+	if err == nil {
+		t.Fatal("Expected error for duplicate tool name, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "duplicate tool name dup") {
+		t.Errorf("Expected duplicate tool error, got %v", err)
+	}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		t.Fatalf("Expected *errors.Error, got %T", err)
+	}
+
+	if e.Provider != "copy-paste" {
+		t.Errorf("Expected provider 'copy-paste', got '%s'", e.Provider)
+	}
+
+	if len(tools) != 2 || tools[0].Name != "dup" || tools[0].Description != "first" || tools[1].Name != "other" {
+		t.Errorf("Expected first dup and other, got %v", tools)
+	}
+
+	var buf bytes.Buffer
+	previous := logger.GetGlobal()
+	logger.SetGlobal(logger.New(logger.Config{Level: "warn", Output: &buf}))
+	defer logger.SetGlobal(previous)
+
+	if tools := registry.GetAllTools(); len(tools) != 2 {
+		t.Errorf("Expected GetAllTools to drop the duplicate, got %d tools", len(tools))
+	}
+
+	if !strings.Contains(buf.String(), "duplicate tool name dup") {
+		t.Errorf("Expected GetAllTools to log the duplicate, got %q", buf.String())
+	}
+}
+
+// PanicAsyncProvider panics when its tools are refreshed
+type PanicAsyncProvider struct {
+	MockProvider
+}
+
+func (p *PanicAsyncProvider) GetToolsContext(ctx context.Context) ([]utcp.Tool, error) {
+	panic("refresh exploded")
+}
+
+func TestGetAllToolsContextRecoversPanic(t *testing.T) {
+	registry := NewRegistry()
+	registry.addProvider("panicky", &PanicAsyncProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "panicky", Enabled: true}},
+	}, "")
+
+	_, err := registry.GetAllToolsContext(context.Background())
+	if err == nil {
+		t.Fatal("Expected error from panicking provider, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "refresh exploded") {
+		t.Errorf("Expected panic value in error, got %v", err)
+	}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) || e.Provider != "panicky" {
+		t.Errorf("Expected error annotated with provider 'panicky', got %v", err)
+	}
+}
+
+func TestGetAllToolsContextPanicReopensBreaker(t *testing.T) {
+	registry := NewRegistry()
+	registry.addProvider("panicky", &PanicAsyncProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "panicky", Enabled: true}},
+	}, "")
+
+	now := time.Now()
+	breaker := circuit.NewWithClock(1, time.Minute, func() time.Time { return now })
+	withBreaker := registry.snapshot().clone()
+	withBreaker.breakers["panicky"] = breaker
+	registry.state.Store(withBreaker)
+
+	breaker.RecordFailure()
+	now = now.Add(time.Minute)
+
+	// The half-open probe panics, which must count as a failure
+	if _, err := registry.GetAllToolsContext(context.Background()); err == nil {
+		t.Fatal("Expected error from panicking probe, got nil")
+	}
+
+	if breaker.State() != BreakerOpen {
+		t.Errorf("Expected panicking probe to reopen the breaker, got %s", breaker.State())
+	}
+}
+
+func TestGetAllToolsRecoversPanic(t *testing.T) {
+	registry := NewRegistry()
+	registry.addProvider("panicky", &MockProvider{
+		BaseProvider: BaseProvider{Name: "panicky", Enabled: true},
+		ToolsFunc:    func() []utcp.Tool { panic("get tools exploded") },
+	}, "")
+	registry.addProvider("healthy", &MockProvider{
+		BaseProvider: BaseProvider{Name: "healthy", Enabled: true},
+		ToolsFunc:    func() []utcp.Tool { return []utcp.Tool{{Name: "healthy_tool"}} },
+	}, "")
+
+	tools := registry.GetAllTools()
+	if len(tools) != 1 || tools[0].Name != "healthy_tool" {
+		t.Errorf("Expected only healthy_tool, got %v", tools)
+	}
+
+	tools, err := registry.GetAllToolsContext(context.Background())
+	if len(tools) != 1 || tools[0].Name != "healthy_tool" {
+		t.Errorf("Expected only healthy_tool from GetAllToolsContext, got %v", tools)
+	}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) || e.Provider != "panicky" {
+		t.Fatalf("Expected error annotated with provider 'panicky', got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "get tools exploded") {
+		t.Errorf("Expected panic value in error, got %v", err)
+	}
+}
+
+func TestSetMaxTools(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"p1", "p2"} {
+		registry.addProvider(name, &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Enabled: true},
+			ToolsFunc: func() []utcp.Tool {
+				return []utcp.Tool{{Name: name + "_a"}, {Name: name + "_b"}, {Name: name + "_c"}}
+			},
+		}, "")
+	}
+
+	if tools := registry.GetAllTools(); len(tools) != 6 {
+		t.Fatalf("Expected 6 tools without a cap, got %d", len(tools))
+	}
+
+	registry.SetMaxTools(4)
+
+	if tools := registry.GetAllTools(); len(tools) != 4 {
+		t.Errorf("Expected GetAllTools capped at 4, got %d", len(tools))
+	}
+
+	tools, err := registry.GetAllToolsContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllToolsContext failed: %v", err)
+	}
+	if len(tools) != 4 {
+		t.Errorf("Expected GetAllToolsContext capped at 4, got %d", len(tools))
+	}
+
+	tools, total, err := registry.GetAllToolsWithTotal(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllToolsWithTotal failed: %v", err)
+	}
+	if len(tools) != 4 || total != 6 {
+		t.Errorf("Expected 4 tools of 6 total, got %d of %d", len(tools), total)
+	}
+
+	registry.SetMaxTools(0)
+	if tools := registry.GetAllTools(); len(tools) != 6 {
+		t.Errorf("Expected 6 tools after removing the cap, got %d", len(tools))
+	}
+}
+
+func TestCreateProviderRetriesTransientErrors(t *testing.T) {
+	previous := createBackoff
+	createBackoff = time.Millisecond
+	defer func() { createBackoff = previous }()
+
+	calls := 0
+	registry := NewRegistry()
+	registry.RegisterFactory("flaky", func(config map[string]interface{}) (Provider, error) {
+		calls++
+		if calls <= 2 {
+			return nil, errors.NetworkError("connection refused")
+		}
+		return &MockProvider{BaseProvider: BaseProvider{Name: "flaky", Enabled: true}}, nil
+	})
+
+	if err := registry.CreateProvider("flaky", "flaky", map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected provider to be created after retries, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 factory calls, got %d", calls)
+	}
+
+	if _, exists := registry.GetProvider("flaky"); !exists {
+		t.Error("Expected provider to be registered")
+	}
+}
+
+func TestCreateProviderRetryLimits(t *testing.T) {
+	previous := createBackoff
+	createBackoff = time.Millisecond
+	defer func() { createBackoff = previous }()
+
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+		want     int
+	}{
+		{"transient error exhausts attempts", errors.NetworkError("connection refused"), 2, 2},
+		{"configuration error is not retried", fmt.Errorf("base_url is required"), 3, 1},
+		{"single attempt", errors.TimeoutError("connect"), 1, 1},
+		{"wrapped url error is retried", fmt.Errorf("failed to fetch tools: %w", &url.Error{Op: "Get", URL: "https://catalog.example.com", Err: stderrors.New("connection refused")}), 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			registry := NewRegistry()
+			registry.SetCreateAttempts(tt.attempts)
+			registry.RegisterFactory("broken", func(config map[string]interface{}) (Provider, error) {
+				calls++
+				return nil, tt.err
+			})
+
+			if err := registry.CreateProvider("broken", "broken", map[string]interface{}{}); err == nil {
+				t.Fatal("Expected CreateProvider to fail")
+			}
+
+			if calls != tt.want {
+				t.Errorf("Expected %d factory calls, got %d", tt.want, calls)
+			}
+		})
+	}
+}
+
+func TestAddProvider(t *testing.T) {
+	registry := NewRegistry()
+
+	err := registry.AddProvider(&MockProvider{
+		BaseProvider: BaseProvider{Name: "embedded", Type: "mock", Enabled: true},
+		ToolsFunc: func() []utcp.Tool {
+			return []utcp.Tool{{Name: "embedded_tool"}}
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddProvider failed: %v", err)
+	}
+
+	tools := registry.GetAllTools()
+	if len(tools) != 1 || tools[0].Name != "embedded_tool" {
+		t.Fatalf("Expected embedded_tool from GetAllTools, got %v", tools)
+	}
+
+	if tools[0].Tags[len(tools[0].Tags)-1] != ProviderTagPrefix+"embedded" {
+		t.Errorf("Expected provider tag, got %v", tools[0].Tags)
+	}
+
+	if _, exists := registry.Breaker("embedded"); !exists {
+		t.Error("Expected a circuit breaker for the added provider")
+	}
+
+	err = registry.AddProvider(&MockProvider{BaseProvider: BaseProvider{Name: "embedded", Enabled: true}})
+	if !errors.Is(err, errors.ErrorTypeConfiguration) {
+		t.Errorf("Expected configuration error for a duplicate name, got %v", err)
+	}
+
+	err = registry.AddProvider(&MockProvider{BaseProvider: BaseProvider{Enabled: true}})
+	if !errors.Is(err, errors.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for a missing name, got %v", err)
+	}
+}
+
+func TestAddProviderCachesTools(t *testing.T) {
+	calls := 0
+	registry := NewRegistry()
+	registry.SetToolCacheTTL(time.Minute)
+	registry.AddProvider(&MockProvider{
+		BaseProvider: BaseProvider{Name: "embedded", Enabled: true},
+		ToolsFunc: func() []utcp.Tool {
+			calls++
+			return []utcp.Tool{{Name: "embedded_tool"}}
+		},
+	})
+
+	registry.GetAllTools()
+	registry.GetAllTools()
+
+	if calls != 1 {
+		t.Errorf("Expected tools to be cached, got %d GetTools calls", calls)
+	}
+}
+
+func TestHealthTimeout(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterFactory("mock", func(config map[string]interface{}) (Provider, error) {
+		return &MockProvider{BaseProvider: BaseProvider{Name: config["name"].(string), Enabled: true}}, nil
+	})
+
+	if err := registry.CreateProvider("configured", "mock", map[string]interface{}{"health_timeout": "2s"}); err != nil {
+		t.Fatalf("CreateProvider failed: %v", err)
+	}
+	if err := registry.CreateProvider("default", "mock", map[string]interface{}{}); err != nil {
+		t.Fatalf("CreateProvider failed: %v", err)
+	}
+
+	if timeout := registry.HealthTimeout("configured"); timeout != 2*time.Second {
+		t.Errorf("Expected health timeout 2s, got %s", timeout)
+	}
+	if timeout := registry.HealthTimeout("default"); timeout != DefaultHealthTimeout {
+		t.Errorf("Expected default health timeout %s, got %s", DefaultHealthTimeout, timeout)
+	}
+
+	for _, value := range []interface{}{"soon", "-1s", 5} {
+		err := registry.CreateProvider("invalid", "mock", map[string]interface{}{"health_timeout": value})
+		if !errors.Is(err, errors.ErrorTypeConfiguration) {
+			t.Errorf("Expected configuration error for health_timeout %v, got %v", value, err)
+		}
+	}
+}
+
+func TestSetProviderEnabled(t *testing.T) {
+	registry := NewRegistry()
+	registry.addProvider("p1", &MockProvider{
+		BaseProvider: BaseProvider{Name: "p1", Enabled: true},
+	}, "")
+
+	if err := registry.SetProviderEnabled("p1", false); err != nil {
+		t.Fatalf("SetProviderEnabled failed: %v", err)
+	}
+
+	if len(registry.GetEnabledProviders()) != 0 {
+		t.Error("Expected no enabled providers after disabling")
+	}
+
+	if err := registry.SetProviderEnabled("p1", true); err != nil {
+		t.Fatalf("SetProviderEnabled failed: %v", err)
+	}
+
+	if len(registry.GetEnabledProviders()) != 1 {
+		t.Error("Expected provider to be enabled again")
+	}
+
+	err := registry.SetProviderEnabled("missing", true)
+	if err == nil {
+		t.Error("Expected error for unknown provider, got nil")
+	}
+
+	if !errors.Is(err, errors.ErrorTypeNotFound) {
+		t.Errorf("Expected not_found error, got %v", err)
+	}
+}
+
 func TestClear(t *testing.T) {
 	registry := NewRegistry()
 
 	// Add some providers
-	registry.providers["p1"] = &MockProvider{
+	registry.addProvider("p1", &MockProvider{
 		BaseProvider: BaseProvider{Name: "p1"},
-	}
-	registry.providers["p2"] = &MockProvider{
+	}, "")
+	registry.addProvider("p2", &MockProvider{
 		BaseProvider: BaseProvider{Name: "p2"},
-	}
+	}, "")
 
-	if len(registry.providers) != 2 {
-		t.Errorf("Expected 2 providers before clear, got %d", len(registry.providers))
+	if len(registry.snapshot().providers) != 2 {
+		t.Errorf("Expected 2 providers before clear, got %d", len(registry.snapshot().providers))
 	}
 
 	// Clear the registry
 	registry.Clear()
 
-	if len(registry.providers) != 0 {
-		t.Errorf("Expected 0 providers after clear, got %d", len(registry.providers))
+	if len(registry.snapshot().providers) != 0 {
+		t.Errorf("Expected 0 providers after clear, got %d", len(registry.snapshot().providers))
 	}
 
 	// Verify factories are not cleared
@@ -311,6 +937,74 @@ func TestClear(t *testing.T) {
 	}
 }
 
+func TestHeadersFromConfig(t *testing.T) {
+	headers, err := HeadersFromConfig(map[string]interface{}{})
+	if err != nil || headers != nil {
+		t.Errorf("Expected no headers without config, got %v, %v", headers, err)
+	}
+
+	headers, err = HeadersFromConfig(map[string]interface{}{
+		"default_headers": map[string]interface{}{"X-Corp-Context": "engineering"},
+	})
+	if err != nil {
+		t.Fatalf("HeadersFromConfig failed: %v", err)
+	}
+	if headers["X-Corp-Context"] != "engineering" {
+		t.Errorf("Expected X-Corp-Context 'engineering', got '%s'", headers["X-Corp-Context"])
+	}
+
+	_, err = HeadersFromConfig(map[string]interface{}{
+		"default_headers": map[string]interface{}{"X-Retries": 3},
+	})
+	if !errors.Is(err, errors.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for non-string header, got %v", err)
+	}
+
+	if _, err := HeadersFromConfig(map[string]interface{}{"default_headers": "X-Corp-Context"}); err == nil {
+		t.Error("Expected error when default_headers is not a map")
+	}
+}
+
+func TestEnvFromToolAuth(t *testing.T) {
+	tools := []utcp.Tool{
+		{Name: "a", ToolProvider: utcp.HTTPProvider("a", "https://api.example.com", "GET", utcp.BasicAuth("API_USER", "API_PASSWORD"))},
+		{Name: "b", ToolProvider: utcp.HTTPProvider("b", "https://api.example.com", "GET", utcp.BearerAuth("API_TOKEN"))},
+		{Name: "c", ToolProvider: utcp.HTTPProvider("c", "https://api.example.com", "GET", utcp.BasicAuth("API_USER", "API_PASSWORD"))},
+		{Name: "d", ToolProvider: utcp.HTTPProvider("d", "https://api.example.com", "GET", nil)},
+	}
+
+	env := EnvFromToolAuth(tools)
+	expected := []string{"API_PASSWORD", "API_TOKEN", "API_USER"}
+	if len(env) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, env)
+	}
+	for i, name := range expected {
+		if env[i] != name {
+			t.Errorf("Expected env var %s at %d, got %s", name, i, env[i])
+		}
+	}
+
+	if env := EnvFromToolAuth(nil); len(env) != 0 {
+		t.Errorf("Expected no env vars without tools, got %v", env)
+	}
+
+	// Alternatives under auth_options, both as built and as decoded from JSON
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"auth_options": [{"auth_type": "bearer", "token": "$JSON_TOKEN"}]}`), &decoded); err != nil {
+		t.Fatalf("Failed to decode provider: %v", err)
+	}
+	tools = []utcp.Tool{
+		{Name: "multi", ToolProvider: utcp.HTTPProviderMultiAuth("multi", "https://api.example.com", "GET",
+			utcp.BearerAuth("API_TOKEN"), utcp.BasicAuth("API_USER", "API_PASSWORD"))},
+		{Name: "decoded", ToolProvider: decoded},
+	}
+
+	env = EnvFromToolAuth(tools)
+	if strings.Join(env, ",") != "API_PASSWORD,API_TOKEN,API_USER,JSON_TOKEN" {
+		t.Errorf("Expected env vars from auth_options, got %v", env)
+	}
+}
+
 func TestBaseProvider(t *testing.T) {
 	base := BaseProvider{
 		Name:    "test-provider",
@@ -331,6 +1025,10 @@ func TestBaseProvider(t *testing.T) {
 		t.Error("Expected provider to be enabled")
 	}
 
+	if len(base.RequiredEnv()) != 0 {
+		t.Errorf("Expected no required env by default, got %v", base.RequiredEnv())
+	}
+
 	// Test disabled provider
 	base.Enabled = false
 	if base.IsEnabled() {
@@ -385,3 +1083,432 @@ func TestConcurrency(t *testing.T) {
 		t.Errorf("Expected 10 providers, got %d", len(providers))
 	}
 }
+
+// concurrentRegistry returns a registry with a factory for always-enabled
+// mock providers
+func concurrentRegistry() *Registry {
+	registry := NewRegistry()
+	registry.RegisterFactory("concurrent", func(config map[string]interface{}) (Provider, error) {
+		name, _ := config["name"].(string)
+		return &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Type: "concurrent", Enabled: true},
+		}, nil
+	})
+	return registry
+}
+
+func TestConcurrentReadsDuringReload(t *testing.T) {
+	registry := concurrentRegistry()
+	for i := 0; i < 5; i++ {
+		registry.CreateProvider(fmt.Sprintf("provider-%d", i), "concurrent", map[string]interface{}{})
+	}
+
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				for _, provider := range registry.GetEnabledProviders() {
+					registry.Breaker(provider.GetName())
+				}
+				registry.GetAllTools()
+				registry.GetAllToolsContext(context.Background())
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		writers.Add(1)
+		go func(id int) {
+			defer writers.Done()
+			for j := 0; j < 50; j++ {
+				name := fmt.Sprintf("churn-%d-%d", id, j)
+				registry.CreateProvider(name, "concurrent", map[string]interface{}{})
+				registry.SetProviderEnabled(name, j%2 == 0)
+			}
+		}(i)
+	}
+
+	for i := 0; i < 20; i++ {
+		err := registry.Reload(func(next *Registry) error {
+			for j := 0; j < 5; j++ {
+				if err := next.CreateProvider(fmt.Sprintf("provider-%d", j), "concurrent", map[string]interface{}{}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	}
+
+	writers.Wait()
+	close(stop)
+	readers.Wait()
+
+	for _, provider := range registry.GetAllProviders() {
+		if _, ok := registry.Breaker(provider.GetName()); !ok {
+			t.Errorf("Expected breaker for provider %s", provider.GetName())
+		}
+		if _, ok := registry.HealthBreaker(provider.GetName()); !ok {
+			t.Errorf("Expected health breaker for provider %s", provider.GetName())
+		}
+	}
+}
+
+// lockedProviders mirrors the registry's former design, where every read
+// took a shared lock on a mutable map. It is the baseline for the
+// GetEnabledProviders benchmarks.
+type lockedProviders struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func (l *lockedProviders) add(name string, provider Provider) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.providers[name] = provider
+}
+
+func (l *lockedProviders) enabled() []Provider {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	providers := make([]Provider, 0)
+	for _, provider := range l.providers {
+		if provider.IsEnabled() {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// benchmarkRegistryReads measures parallel reads while a writer replaces a
+// provider every 100µs, as under frequent hot reloads
+func benchmarkRegistryReads(b *testing.B, add func(name string), read func()) {
+	for i := 0; i < 20; i++ {
+		add(fmt.Sprintf("provider-%d", i))
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	ticker := time.NewTicker(100 * time.Microsecond)
+	defer ticker.Stop()
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				add(fmt.Sprintf("provider-%d", i%20))
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			read()
+		}
+	})
+	b.StopTimer()
+
+	close(stop)
+	<-done
+}
+
+// BenchmarkRegistryGetEnabledProviders compares read throughput of the
+// snapshot registry against the former locked map under parallel load
+func BenchmarkRegistryGetEnabledProviders(b *testing.B) {
+	b.Run("snapshot", func(b *testing.B) {
+		registry := concurrentRegistry()
+		benchmarkRegistryReads(b,
+			func(name string) { registry.CreateProvider(name, "concurrent", map[string]interface{}{}) },
+			func() { registry.GetEnabledProviders() },
+		)
+	})
+
+	b.Run("locked", func(b *testing.B) {
+		locked := &lockedProviders{providers: make(map[string]Provider)}
+		benchmarkRegistryReads(b,
+			func(name string) {
+				locked.add(name, &MockProvider{BaseProvider: BaseProvider{Name: name, Enabled: true}})
+			},
+			func() { locked.enabled() },
+		)
+	})
+}
+
+// BenchmarkRegistryGetAllTools measures parallel discovery reads while
+// providers are replaced
+func BenchmarkRegistryGetAllTools(b *testing.B) {
+	registry := concurrentRegistry()
+	benchmarkRegistryReads(b,
+		func(name string) { registry.CreateProvider(name, "concurrent", map[string]interface{}{}) },
+		func() { registry.GetAllTools() },
+	)
+}
+
+func newMockFactoryRegistry() *Registry {
+	registry := NewRegistry()
+	registry.RegisterFactory("mock", func(config map[string]interface{}) (Provider, error) {
+		name, _ := config["name"].(string)
+		baseURL, _ := config["base_url"].(string)
+
+		return &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Type: "mock", Enabled: true, BaseURL: baseURL},
+		}, nil
+	})
+	return registry
+}
+
+func TestReloadPreservesBreakerState(t *testing.T) {
+	registry := newMockFactoryRegistry()
+	registry.CreateProvider("stable", "mock", map[string]interface{}{"base_url": "https://stable.example.com"})
+	registry.CreateProvider("moved", "mock", map[string]interface{}{"base_url": "https://old.example.com"})
+
+	for _, name := range []string{"stable", "moved"} {
+		breaker, _ := registry.Breaker(name)
+		health, _ := registry.HealthBreaker(name)
+		for i := 0; i < DefaultBreakerThreshold; i++ {
+			breaker.RecordFailure()
+			health.RecordFailure()
+		}
+	}
+
+	err := registry.Reload(func(next *Registry) error {
+		if err := next.CreateProvider("stable", "mock", map[string]interface{}{"base_url": "https://stable.example.com"}); err != nil {
+			return err
+		}
+		if err := next.CreateProvider("moved", "mock", map[string]interface{}{"base_url": "https://new.example.com"}); err != nil {
+			return err
+		}
+		return next.CreateProvider("added", "mock", map[string]interface{}{"base_url": "https://added.example.com"})
+	})
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if len(registry.GetAllProviders()) != 3 {
+		t.Errorf("Expected 3 providers after reload, got %d", len(registry.GetAllProviders()))
+	}
+
+	stable, _ := registry.Breaker("stable")
+	if stable.State() != BreakerOpen {
+		t.Errorf("Expected unchanged provider breaker to stay %s, got %s", BreakerOpen, stable.State())
+	}
+
+	if health, _ := registry.HealthBreaker("stable"); health.State() != BreakerOpen {
+		t.Errorf("Expected unchanged provider health breaker to stay %s, got %s", BreakerOpen, health.State())
+	}
+
+	if health, _ := registry.HealthBreaker("moved"); health.State() != BreakerClosed {
+		t.Errorf("Expected changed provider health breaker to reset to %s, got %s", BreakerClosed, health.State())
+	}
+
+	moved, _ := registry.Breaker("moved")
+	if moved.State() != BreakerClosed {
+		t.Errorf("Expected changed provider breaker to reset to %s, got %s", BreakerClosed, moved.State())
+	}
+
+	added, exists := registry.Breaker("added")
+	if !exists {
+		t.Fatal("Expected breaker for new provider")
+	}
+	if added.State() != BreakerClosed {
+		t.Errorf("Expected new provider breaker %s, got %s", BreakerClosed, added.State())
+	}
+}
+
+func TestReloadFailureKeepsProviders(t *testing.T) {
+	registry := newMockFactoryRegistry()
+	registry.CreateProvider("stable", "mock", map[string]interface{}{})
+
+	err := registry.Reload(func(next *Registry) error {
+		return fmt.Errorf("bad config")
+	})
+	if err == nil {
+		t.Fatal("Expected error from failed reload, got nil")
+	}
+
+	if _, exists := registry.GetProvider("stable"); !exists {
+		t.Error("Expected existing provider to survive a failed reload")
+	}
+}
+
+func TestGetAllToolsContextOpenBreaker(t *testing.T) {
+	var inFlight, maxSeen int32
+
+	registry := NewRegistry()
+	registry.addProvider("flaky", &SlowAsyncProvider{
+		MockProvider: MockProvider{BaseProvider: BaseProvider{Name: "flaky", Enabled: true}},
+		InFlight:     &inFlight,
+		MaxSeen:      &maxSeen,
+		Err:          fmt.Errorf("upstream down"),
+	}, "")
+	withBreaker := registry.snapshot().clone()
+	withBreaker.breakers["flaky"] = NewCircuitBreaker(1, time.Minute)
+	registry.state.Store(withBreaker)
+
+	if _, err := registry.GetAllToolsContext(context.Background()); err == nil {
+		t.Fatal("Expected error from failing provider, got nil")
+	}
+
+	breaker, _ := registry.Breaker("flaky")
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("Expected breaker %s, got %s", BreakerOpen, breaker.State())
+	}
+
+	// The open breaker short-circuits the next refresh
+	_, err := registry.GetAllToolsContext(context.Background())
+	if err == nil {
+		t.Fatal("Expected error while breaker is open, got nil")
+	}
+	if !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Errorf("Expected circuit breaker error, got %v", err)
+	}
+}
+
+// slowProviders registers n providers whose GetTools sleeps for delay, as a
+// dynamic provider making a network call would
+func slowProviders(registry *Registry, n int, delay time.Duration) {
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("slow-%d", i)
+		registry.addProvider(name, &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Enabled: true},
+			ToolsFunc: func() []utcp.Tool {
+				time.Sleep(delay)
+				return []utcp.Tool{{Name: name + "_tool"}}
+			},
+		}, "")
+	}
+}
+
+func TestGetAllToolsConcurrent(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetMaxConcurrentRefreshes(4)
+	slowProviders(registry, 4, 50*time.Millisecond)
+
+	start := time.Now()
+	tools := registry.GetAllTools()
+	elapsed := time.Since(start)
+
+	if elapsed >= 150*time.Millisecond {
+		t.Errorf("Expected providers to be called concurrently, took %v", elapsed)
+	}
+
+	if len(tools) != 4 {
+		t.Fatalf("Expected 4 tools, got %d", len(tools))
+	}
+
+	for i, tool := range tools {
+		if want := fmt.Sprintf("slow-%d_tool", i); tool.Name != want {
+			t.Errorf("Expected tool %d to be %s, got %s", i, want, tool.Name)
+		}
+	}
+}
+
+func TestGetAllToolsContextCallsSyncProvidersConcurrently(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetMaxConcurrentRefreshes(4)
+	slowProviders(registry, 4, 50*time.Millisecond)
+
+	start := time.Now()
+	tools, err := registry.GetAllToolsContext(context.Background())
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("GetAllToolsContext failed: %v", err)
+	}
+
+	if elapsed >= 150*time.Millisecond {
+		t.Errorf("Expected sync providers to be called concurrently, took %v", elapsed)
+	}
+
+	if len(tools) != 4 {
+		t.Fatalf("Expected 4 tools, got %d", len(tools))
+	}
+
+	for i, tool := range tools {
+		if want := fmt.Sprintf("slow-%d_tool", i); tool.Name != want {
+			t.Errorf("Expected tool %d to be %s, got %s", i, want, tool.Name)
+		}
+	}
+}
+
+func TestGetAllToolsRespectsConcurrencyLimit(t *testing.T) {
+	var running, peak atomic.Int32
+
+	registry := NewRegistry()
+	registry.SetMaxConcurrentRefreshes(2)
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("p%d", i)
+		registry.addProvider(name, &MockProvider{
+			BaseProvider: BaseProvider{Name: name, Enabled: true},
+			ToolsFunc: func() []utcp.Tool {
+				current := running.Add(1)
+				defer running.Add(-1)
+				for {
+					previous := peak.Load()
+					if current <= previous || peak.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			},
+		}, "")
+	}
+
+	registry.GetAllTools()
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent GetTools calls, got %d", peak.Load())
+	}
+}
+
+func benchmarkGetAllToolsSlow(b *testing.B, limit int, collect func(*Registry)) {
+	registry := NewRegistry()
+	registry.SetMaxConcurrentRefreshes(limit)
+	slowProviders(registry, 8, 2*time.Millisecond)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		collect(registry)
+	}
+}
+
+// BenchmarkGetAllToolsSequential calls eight slow providers one at a time,
+// as GetAllTools used to
+func BenchmarkGetAllToolsSequential(b *testing.B) {
+	benchmarkGetAllToolsSlow(b, 1, func(r *Registry) { r.GetAllTools() })
+}
+
+// BenchmarkGetAllToolsConcurrent calls eight slow providers at once
+func BenchmarkGetAllToolsConcurrent(b *testing.B) {
+	benchmarkGetAllToolsSlow(b, 8, func(r *Registry) { r.GetAllTools() })
+}
+
+// BenchmarkGetAllToolsContextSequential calls eight slow sync providers one
+// at a time through the path /utcp uses
+func BenchmarkGetAllToolsContextSequential(b *testing.B) {
+	benchmarkGetAllToolsSlow(b, 1, func(r *Registry) { r.GetAllToolsContext(context.Background()) })
+}
+
+// BenchmarkGetAllToolsContextConcurrent calls eight slow sync providers at
+// once through the path /utcp uses
+func BenchmarkGetAllToolsContextConcurrent(b *testing.B) {
+	benchmarkGetAllToolsSlow(b, 8, func(r *Registry) { r.GetAllToolsContext(context.Background()) })
+}
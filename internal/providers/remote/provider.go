@@ -0,0 +1,183 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/internal/providers"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// DefaultFetchTimeout bounds how long fetching the remote catalog may take
+const DefaultFetchTimeout = 10 * time.Second
+
+// MaxCatalogBytes is the largest catalog body fetch will read
+const MaxCatalogBytes = 16 << 20
+
+// Provider serves tool definitions fetched from a centrally-managed catalog
+type Provider struct {
+	providers.BaseProvider
+	ToolsURL  string
+	CachePath string
+
+	tools []utcp.Tool
+}
+
+// NewProvider creates a new remote provider with the given tools
+func NewProvider(toolsURL string, tools []utcp.Tool) *Provider {
+	return &Provider{
+		BaseProvider: providers.BaseProvider{
+			Type:    "remote",
+			Enabled: true,
+			BaseURL: toolsURL,
+		},
+		ToolsURL: toolsURL,
+		tools:    tools,
+	}
+}
+
+// NewProviderFromConfig creates a new remote provider from configuration.
+// Tools are fetched from tools_url; on success they are written to
+// cache_path (if set), and on failure they are read back from it.
+func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, error) {
+	name, _ := config["name"].(string)
+	toolsURL, _ := config["tools_url"].(string)
+	cachePath, _ := config["cache_path"].(string)
+	enabled, _ := config["enabled"].(bool)
+
+	if toolsURL == "" {
+		toolsURL, _ = config["base_url"].(string)
+	}
+
+	if toolsURL == "" {
+		return nil, fmt.Errorf("tools_url is required")
+	}
+
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: DefaultFetchTimeout}
+	tools, err := LoadTools(client, toolsURL, cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewProvider(toolsURL, tools)
+	provider.Name = name
+	provider.Enabled = enabled
+	provider.CachePath = cachePath
+	provider.Headers = headers
+
+	return provider, nil
+}
+
+// LoadTools fetches tools from toolsURL, falling back to the cache at
+// cachePath when the catalog cannot be fetched
+func LoadTools(client *http.Client, toolsURL, cachePath string) ([]utcp.Tool, error) {
+	data, fetchErr := fetch(client, toolsURL)
+	if fetchErr == nil {
+		tools, err := parseTools(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool catalog from %s: %w", toolsURL, err)
+		}
+
+		if cachePath != "" {
+			if err := writeCache(cachePath, data); err != nil {
+				return nil, err
+			}
+		}
+
+		return tools, nil
+	}
+
+	if cachePath == "" {
+		return nil, fmt.Errorf("failed to fetch tools from %s: %w", toolsURL, fetchErr)
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tools from %s (%v) and read cache: %w", toolsURL, fetchErr, err)
+	}
+
+	tools, err := parseTools(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cached tool catalog %s: %w", cachePath, err)
+	}
+
+	return tools, nil
+}
+
+// RequiredEnv returns the environment variables referenced by tool auth
+func (p *Provider) RequiredEnv() []string {
+	return providers.EnvFromToolAuth(p.tools)
+}
+
+// GetTools returns the tools loaded from the remote catalog, adding the
+// provider's default headers to any that do not already set them
+func (p *Provider) GetTools() []utcp.Tool {
+	tools := make([]utcp.Tool, len(p.tools))
+	copy(tools, p.tools)
+	return utcp.ApplyHeaders(tools, p.Headers)
+}
+
+// fetch retrieves the catalog body from url, failing if it is larger than
+// MaxCatalogBytes
+func fetch(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxCatalogBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxCatalogBytes {
+		return nil, fmt.Errorf("catalog exceeds %d bytes", MaxCatalogBytes)
+	}
+
+	return data, nil
+}
+
+// parseTools decodes and validates a JSON array of tools, inferring the input
+// location of tools that do not declare one
+func parseTools(data []byte) ([]utcp.Tool, error) {
+	var tools []utcp.Tool
+	if err := json.Unmarshal(data, &tools); err != nil {
+		return nil, err
+	}
+
+	for _, tool := range tools {
+		if err := tool.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return utcp.WithInputLocations(tools), nil
+}
+
+// writeCache stores the catalog body at path
+func writeCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write tool cache %s: %w", path, err)
+	}
+
+	return nil
+}
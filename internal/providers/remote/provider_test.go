@@ -0,0 +1,235 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rh-utcp/rh-utcp/internal/providers"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func catalog() []utcp.Tool {
+	return []utcp.Tool{
+		{
+			Name:        "catalog_get_item",
+			Description: "Get a catalog item",
+			Inputs: utcp.Schema{
+				Type:       "object",
+				Properties: map[string]utcp.Property{"id": {Type: "string", Description: "Item ID"}},
+				Required:   []string{"id"},
+			},
+			Outputs: utcp.Schema{Type: "object"},
+			ToolProvider: utcp.HTTPProvider(
+				"catalog_get_item",
+				"https://catalog.example.com/items/${id}",
+				"GET",
+				utcp.BearerAuth("CATALOG_TOKEN"),
+			),
+		},
+		{
+			Name:    "catalog_list_items",
+			Inputs:  utcp.Schema{Type: "object"},
+			Outputs: utcp.Schema{Type: "array"},
+			ToolProvider: utcp.HTTPProvider(
+				"catalog_list_items",
+				"https://catalog.example.com/items",
+				"GET",
+				utcp.APIKeyAuth("CATALOG_API_KEY", "X-API-Key"),
+			),
+		},
+	}
+}
+
+func newCatalogServer(t *testing.T, tools interface{}) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tools)
+	}))
+}
+
+func TestNewProviderFromConfig(t *testing.T) {
+	server := newCatalogServer(t, catalog())
+	defer server.Close()
+
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"name":      "catalog",
+		"enabled":   true,
+		"tools_url": server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	if provider.GetType() != "remote" {
+		t.Errorf("Expected type 'remote', got %s", provider.GetType())
+	}
+
+	tools := provider.GetTools()
+	if len(tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(tools))
+	}
+
+	if tools[0].Name != "catalog_get_item" {
+		t.Errorf("Expected first tool 'catalog_get_item', got %s", tools[0].Name)
+	}
+
+	required := provider.RequiredEnv()
+	if len(required) != 2 || required[0] != "CATALOG_API_KEY" || required[1] != "CATALOG_TOKEN" {
+		t.Errorf("Expected CATALOG_API_KEY and CATALOG_TOKEN, got %v", required)
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	tools := catalog()
+	tools[1].ToolProvider["headers"] = map[string]string{"X-Corp-Context": "catalog"}
+
+	server := newCatalogServer(t, tools)
+	defer server.Close()
+
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"tools_url":       server.URL,
+		"default_headers": map[string]interface{}{"X-Corp-Context": "engineering"},
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	expected := []string{"engineering", "catalog"}
+	for i, tool := range provider.GetTools() {
+		headers, _ := tool.ToolProvider["headers"].(map[string]string)
+		if headers["X-Corp-Context"] != expected[i] {
+			t.Errorf("Tool %s: expected X-Corp-Context %q, got %v", tool.Name, expected[i], tool.ToolProvider["headers"])
+		}
+	}
+}
+
+func TestNewProviderFromConfigMissingURL(t *testing.T) {
+	if _, err := NewProviderFromConfig(map[string]interface{}{}); err == nil {
+		t.Error("Expected error for missing tools_url, got nil")
+	}
+}
+
+func TestInvalidCatalog(t *testing.T) {
+	invalid := catalog()
+	invalid[1].Name = ""
+
+	server := newCatalogServer(t, invalid)
+	defer server.Close()
+
+	_, err := NewProviderFromConfig(map[string]interface{}{
+		"tools_url": server.URL,
+	})
+	if err == nil {
+		t.Error("Expected error for invalid tool in catalog, got nil")
+	}
+}
+
+func TestOversizedCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("["))
+		w.Write(bytes.Repeat([]byte(" "), MaxCatalogBytes))
+		w.Write([]byte("]"))
+	}))
+	defer server.Close()
+
+	_, err := NewProviderFromConfig(map[string]interface{}{
+		"tools_url": server.URL,
+	})
+	if err == nil {
+		t.Fatal("Expected error for oversized catalog, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Expected size limit error, got %v", err)
+	}
+}
+
+func TestCacheFallback(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache", "tools.json")
+
+	server := newCatalogServer(t, catalog())
+	url := server.URL
+
+	// First load populates the cache
+	if _, err := NewProviderFromConfig(map[string]interface{}{
+		"tools_url":  url,
+		"cache_path": cachePath,
+	}); err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("Expected cache to be written: %v", err)
+	}
+
+	// With the catalog unreachable, tools come from the cache
+	server.Close()
+
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"tools_url":  url,
+		"cache_path": cachePath,
+	})
+	if err != nil {
+		t.Fatalf("Expected cache fallback, got error: %v", err)
+	}
+
+	if len(provider.GetTools()) != 2 {
+		t.Errorf("Expected 2 cached tools, got %d", len(provider.GetTools()))
+	}
+}
+
+func TestUnreachableWithoutCache(t *testing.T) {
+	server := newCatalogServer(t, catalog())
+	url := server.URL
+	server.Close()
+
+	_, err := NewProviderFromConfig(map[string]interface{}{
+		"tools_url":  url,
+		"cache_path": filepath.Join(t.TempDir(), "missing.json"),
+	})
+	if err == nil {
+		t.Error("Expected error when catalog and cache are unavailable, got nil")
+	}
+}
+
+func TestCreateProviderRetriesUnreachableCatalog(t *testing.T) {
+	server := newCatalogServer(t, catalog())
+	url := server.URL
+	server.Close()
+
+	calls := 0
+	registry := providers.NewRegistry()
+	registry.SetCreateAttempts(2)
+	registry.RegisterFactory("remote", func(config map[string]interface{}) (providers.Provider, error) {
+		calls++
+		return NewProviderFromConfig(config)
+	})
+
+	err := registry.CreateProvider("catalog", "remote", map[string]interface{}{"tools_url": url})
+	if err == nil {
+		t.Fatal("Expected error for an unreachable catalog, got nil")
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected the unreachable catalog to be retried, got %d factory calls", calls)
+	}
+}
+
+func TestGetToolsReturnsCopy(t *testing.T) {
+	provider := NewProvider("https://catalog.example.com", catalog())
+
+	tools := provider.GetTools()
+	tools[0].Name = "mutated"
+
+	if provider.GetTools()[0].Name != "catalog_get_item" {
+		t.Error("Expected GetTools to return a copy")
+	}
+}
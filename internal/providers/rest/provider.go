@@ -0,0 +1,259 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rh-utcp/rh-utcp/internal/providers"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// AuthConfig declares how a tool authenticates. Values name environment
+// variables that agents read at call time, never the secrets themselves.
+type AuthConfig struct {
+	Type            string `json:"type"`
+	UsernameEnv     string `json:"username_env"`
+	PasswordEnv     string `json:"password_env"`
+	TokenEnv        string `json:"token_env"`
+	APIKeyEnv       string `json:"api_key_env"`
+	Header          string `json:"header"`
+	ClientIDEnv     string `json:"client_id_env"`
+	ClientSecretEnv string `json:"client_secret_env"`
+	TokenURLEnv     string `json:"token_url_env"`
+}
+
+// ToolConfig declares a single tool served by a REST provider
+type ToolConfig struct {
+	Name               string      `json:"name"`
+	Description        string      `json:"description"`
+	Method             string      `json:"method"`
+	Path               string      `json:"path"`
+	Inputs             utcp.Schema `json:"inputs"`
+	Outputs            utcp.Schema `json:"outputs"`
+	Tags               []string    `json:"tags"`
+	Auth               *AuthConfig `json:"auth"`
+	Deprecated         bool        `json:"deprecated"`
+	DeprecationMessage string      `json:"deprecation_message"`
+}
+
+// Config is the options block of a type: rest provider
+type Config struct {
+	Auth  *AuthConfig  `json:"auth"`
+	Tools []ToolConfig `json:"tools"`
+}
+
+// Provider serves tools declared entirely in configuration
+type Provider struct {
+	providers.BaseProvider
+	tools []utcp.Tool
+}
+
+// NewProvider creates a new REST provider from declared tools
+func NewProvider(baseURL string, config Config) (*Provider, error) {
+	provider := &Provider{
+		BaseProvider: providers.BaseProvider{
+			Type:    "rest",
+			Enabled: true,
+			BaseURL: baseURL,
+		},
+	}
+
+	if len(config.Tools) == 0 {
+		return nil, fmt.Errorf("at least one tool is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, toolConfig := range config.Tools {
+		tool, err := buildTool(baseURL, toolConfig, config.Auth)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[tool.Name] {
+			return nil, fmt.Errorf("duplicate tool name %s", tool.Name)
+		}
+		seen[tool.Name] = true
+
+		provider.tools = append(provider.tools, tool)
+	}
+
+	return provider, nil
+}
+
+// NewProviderFromConfig creates a new REST provider from configuration. Tool
+// definitions are read from the "tools" key and an optional provider-wide
+// "auth" key, which tools without their own auth inherit.
+func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, error) {
+	name, _ := config["name"].(string)
+	baseURL, _ := config["base_url"].(string)
+	enabled, _ := config["enabled"].(bool)
+
+	if baseURL == "" {
+		return nil, fmt.Errorf("base_url is required")
+	}
+
+	restConfig, err := decodeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// A provider configured for oauth2 without its own auth block uses the
+	// OAuth2 credentials named after the provider, e.g. INVENTORY_CLIENT_ID
+	if oauth2, ok := providers.OAuth2EnvFromConfig(config, envPrefix(name)); ok && restConfig.Auth == nil {
+		restConfig.Auth = &AuthConfig{
+			Type:            "oauth2",
+			ClientIDEnv:     oauth2.ClientID,
+			ClientSecretEnv: oauth2.ClientSecret,
+			TokenURLEnv:     oauth2.TokenURL,
+		}
+	}
+
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider(baseURL, restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.Name = name
+	provider.Enabled = enabled
+	provider.Headers = headers
+
+	return provider, nil
+}
+
+// envPrefix converts a provider name such as "my-api" to an environment
+// variable prefix such as "MY_API"
+func envPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// RequiredEnv returns the environment variables referenced by tool auth
+func (p *Provider) RequiredEnv() []string {
+	return providers.EnvFromToolAuth(p.tools)
+}
+
+// GetTools returns the tools declared in configuration
+func (p *Provider) GetTools() []utcp.Tool {
+	tools := make([]utcp.Tool, len(p.tools))
+	copy(tools, p.tools)
+	return utcp.ApplyHeaders(tools, p.Headers)
+}
+
+// decodeConfig converts the loosely-typed config map into Config
+func decodeConfig(config map[string]interface{}) (Config, error) {
+	var restConfig Config
+
+	data, err := json.Marshal(map[string]interface{}{
+		"auth":  config["auth"],
+		"tools": config["tools"],
+	})
+	if err != nil {
+		return restConfig, fmt.Errorf("invalid rest provider config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &restConfig); err != nil {
+		return restConfig, fmt.Errorf("invalid rest provider config: %w", err)
+	}
+
+	return restConfig, nil
+}
+
+// buildTool converts a declared tool into a utcp.Tool
+func buildTool(baseURL string, config ToolConfig, defaultAuth *AuthConfig) (utcp.Tool, error) {
+	if config.Name == "" {
+		return utcp.Tool{}, fmt.Errorf("tool name is required")
+	}
+
+	if !strings.HasPrefix(config.Path, "/") {
+		return utcp.Tool{}, fmt.Errorf("tool %s: path must start with /", config.Name)
+	}
+
+	authConfig := config.Auth
+	if authConfig == nil {
+		authConfig = defaultAuth
+	}
+
+	auth, err := buildAuth(authConfig)
+	if err != nil {
+		return utcp.Tool{}, fmt.Errorf("tool %s: %w", config.Name, err)
+	}
+
+	inputs := config.Inputs
+	if inputs.Type == "" {
+		inputs.Type = "object"
+	}
+
+	outputs := config.Outputs
+	if outputs.Type == "" {
+		outputs.Type = "object"
+	}
+
+	tool := utcp.Tool{
+		Name:               config.Name,
+		Description:        config.Description,
+		Inputs:             inputs,
+		Outputs:            outputs,
+		Tags:               config.Tags,
+		Deprecated:         config.Deprecated,
+		DeprecationMessage: config.DeprecationMessage,
+		ToolProvider: utcp.HTTPProvider(
+			config.Name,
+			strings.TrimRight(baseURL, "/")+config.Path,
+			strings.ToUpper(config.Method),
+			auth,
+		),
+	}
+
+	tool.InputLocation = utcp.InferInputLocation(tool)
+
+	if err := tool.Validate(); err != nil {
+		return utcp.Tool{}, err
+	}
+
+	return tool, nil
+}
+
+// buildAuth converts declared auth into a tool provider auth block
+func buildAuth(config *AuthConfig) (map[string]interface{}, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	switch config.Type {
+	case "basic":
+		if config.UsernameEnv == "" || config.PasswordEnv == "" {
+			return nil, fmt.Errorf("basic auth requires username_env and password_env")
+		}
+		return utcp.BasicAuth(config.UsernameEnv, config.PasswordEnv), nil
+	case "bearer":
+		if config.TokenEnv == "" {
+			return nil, fmt.Errorf("bearer auth requires token_env")
+		}
+		return utcp.BearerAuth(config.TokenEnv), nil
+	case "api_key":
+		if config.APIKeyEnv == "" || config.Header == "" {
+			return nil, fmt.Errorf("api_key auth requires api_key_env and header")
+		}
+		return utcp.APIKeyAuth(config.APIKeyEnv, config.Header), nil
+	case "oauth2":
+		if config.ClientIDEnv == "" || config.ClientSecretEnv == "" || config.TokenURLEnv == "" {
+			return nil, fmt.Errorf("oauth2 auth requires client_id_env, client_secret_env and token_url_env")
+		}
+		return utcp.OAuth2Auth(config.ClientIDEnv, config.ClientSecretEnv, config.TokenURLEnv), nil
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q", config.Type)
+	}
+}
@@ -0,0 +1,292 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func testConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name":     "inventory",
+		"enabled":  true,
+		"base_url": "https://inventory.example.com/api/",
+		"auth": map[string]interface{}{
+			"type":      "bearer",
+			"token_env": "INVENTORY_TOKEN",
+		},
+		"tools": []interface{}{
+			map[string]interface{}{
+				"name":        "inventory_get_host",
+				"description": "Get a host by name",
+				"method":      "get",
+				"path":        "/hosts/${hostname}",
+				"inputs": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"hostname": map[string]interface{}{
+							"type":        "string",
+							"description": "Host name",
+						},
+					},
+					"required": []interface{}{"hostname"},
+				},
+				"tags": []interface{}{"inventory", "hosts"},
+			},
+			map[string]interface{}{
+				"name":        "inventory_create_host",
+				"description": "Register a new host",
+				"method":      "POST",
+				"path":        "/hosts",
+				"auth": map[string]interface{}{
+					"type":         "basic",
+					"username_env": "INVENTORY_USER",
+					"password_env": "INVENTORY_PASSWORD",
+				},
+			},
+		},
+	}
+}
+
+func TestNewProviderFromConfig(t *testing.T) {
+	provider, err := NewProviderFromConfig(testConfig())
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	if provider.GetName() != "inventory" {
+		t.Errorf("Expected name 'inventory', got %s", provider.GetName())
+	}
+
+	if provider.GetType() != "rest" {
+		t.Errorf("Expected type 'rest', got %s", provider.GetType())
+	}
+
+	tools := provider.GetTools()
+	if len(tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(tools))
+	}
+
+	getHost := tools[0]
+	if getHost.ToolProvider["url"] != "https://inventory.example.com/api/hosts/${hostname}" {
+		t.Errorf("Unexpected URL: %v", getHost.ToolProvider["url"])
+	}
+
+	if getHost.ToolProvider["http_method"] != "GET" {
+		t.Errorf("Expected http_method 'GET', got %v", getHost.ToolProvider["http_method"])
+	}
+
+	if len(getHost.Inputs.Required) != 1 || getHost.Inputs.Required[0] != "hostname" {
+		t.Errorf("Expected 'hostname' to be required, got %v", getHost.Inputs.Required)
+	}
+
+	auth := getHost.ToolProvider["auth"].(map[string]interface{})
+	if auth["token"] != "$INVENTORY_TOKEN" {
+		t.Errorf("Expected inherited bearer token auth, got %v", auth)
+	}
+
+	createHost := tools[1]
+	if createHost.Inputs.Type != "object" || createHost.Outputs.Type != "object" {
+		t.Errorf("Expected default object schemas, got %s/%s", createHost.Inputs.Type, createHost.Outputs.Type)
+	}
+
+	auth = createHost.ToolProvider["auth"].(map[string]interface{})
+	if auth["auth_type"] != "basic" {
+		t.Errorf("Expected tool-level basic auth, got %v", auth["auth_type"])
+	}
+
+	required := provider.RequiredEnv()
+	expected := []string{"INVENTORY_PASSWORD", "INVENTORY_TOKEN", "INVENTORY_USER"}
+	if len(required) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, required)
+	}
+	for i, name := range expected {
+		if required[i] != name {
+			t.Errorf("Expected required env var %s, got %s", name, required[i])
+		}
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	config := testConfig()
+	config["default_headers"] = map[string]interface{}{"X-Corp-Context": "engineering"}
+
+	provider, err := NewProviderFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	for _, tool := range provider.GetTools() {
+		headers, _ := tool.ToolProvider["headers"].(map[string]string)
+		if headers["X-Corp-Context"] != "engineering" {
+			t.Errorf("Tool %s missing default header, got %v", tool.Name, tool.ToolProvider["headers"])
+		}
+	}
+
+	provider, err = NewProviderFromConfig(testConfig())
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	for _, tool := range provider.GetTools() {
+		if _, exists := tool.ToolProvider["headers"]; exists {
+			t.Errorf("Tool %s should have no headers by default", tool.Name)
+		}
+	}
+}
+
+func TestInputFormats(t *testing.T) {
+	config := testConfig()
+	config["tools"] = []interface{}{
+		map[string]interface{}{
+			"name":   "inventory_register_host",
+			"method": "POST",
+			"path":   "/hosts",
+			"inputs": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"console_url": map[string]interface{}{
+						"type":   "string",
+						"format": "uri",
+					},
+					"commissioned_at": map[string]interface{}{
+						"type":   "string",
+						"format": "date-time",
+					},
+				},
+			},
+		},
+	}
+
+	provider, err := NewProviderFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	properties := provider.GetTools()[0].Inputs.Properties
+	if format := properties["console_url"].Format; format != "uri" {
+		t.Errorf("Expected 'console_url' format 'uri', got '%s'", format)
+	}
+	if format := properties["commissioned_at"].Format; format != "date-time" {
+		t.Errorf("Expected 'commissioned_at' format 'date-time', got '%s'", format)
+	}
+}
+
+func TestNewProviderFromConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(config map[string]interface{})
+	}{
+		{"missing base_url", func(c map[string]interface{}) { delete(c, "base_url") }},
+		{"no tools", func(c map[string]interface{}) { delete(c, "tools") }},
+		{"missing tool name", func(c map[string]interface{}) { firstTool(c)["name"] = "" }},
+		{"relative path", func(c map[string]interface{}) { firstTool(c)["path"] = "hosts" }},
+		{"bad method", func(c map[string]interface{}) { firstTool(c)["method"] = "TRACE" }},
+		{"undeclared required input", func(c map[string]interface{}) {
+			firstTool(c)["inputs"] = map[string]interface{}{"required": []interface{}{"id"}}
+		}},
+		{"unknown auth type", func(c map[string]interface{}) { c["auth"] = map[string]interface{}{"type": "magic"} }},
+		{"incomplete oauth2 auth", func(c map[string]interface{}) {
+			c["auth"] = map[string]interface{}{"type": "oauth2", "client_id_env": "INVENTORY_CLIENT_ID"}
+		}},
+		{"duplicate tool", func(c map[string]interface{}) {
+			tools := c["tools"].([]interface{})
+			c["tools"] = append(tools, tools[0])
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			tt.mutate(config)
+
+			if _, err := NewProviderFromConfig(config); err == nil {
+				t.Errorf("Expected error for %s, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestOAuth2Auth(t *testing.T) {
+	config := testConfig()
+	config["auth"] = map[string]interface{}{
+		"type":              "oauth2",
+		"client_id_env":     "INVENTORY_CLIENT_ID",
+		"client_secret_env": "INVENTORY_CLIENT_SECRET",
+		"token_url_env":     "INVENTORY_TOKEN_URL",
+	}
+
+	provider, err := NewProviderFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	auth := provider.GetTools()[0].ToolProvider["auth"].(map[string]interface{})
+	if auth["auth_type"] != "oauth2" {
+		t.Fatalf("Expected oauth2 auth, got %v", auth["auth_type"])
+	}
+
+	if auth["client_id"] != "$INVENTORY_CLIENT_ID" || auth["client_secret"] != "$INVENTORY_CLIENT_SECRET" || auth["token_url"] != "$INVENTORY_TOKEN_URL" {
+		t.Errorf("Expected oauth2 env references, got %v", auth)
+	}
+}
+
+func TestOAuth2AuthType(t *testing.T) {
+	config := testConfig()
+	delete(config, "auth")
+	config["name"] = "my-inventory"
+	config["auth_type"] = "oauth2"
+
+	provider, err := NewProviderFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	tools := provider.GetTools()
+
+	auth := tools[0].ToolProvider["auth"].(map[string]interface{})
+	if auth["auth_type"] != "oauth2" || auth["client_id"] != "$MY_INVENTORY_CLIENT_ID" {
+		t.Errorf("Expected oauth2 auth named after the provider, got %v", auth)
+	}
+
+	auth = tools[1].ToolProvider["auth"].(map[string]interface{})
+	if auth["auth_type"] != "basic" {
+		t.Errorf("Expected tool-level basic auth to be kept, got %v", auth["auth_type"])
+	}
+}
+
+func firstTool(config map[string]interface{}) map[string]interface{} {
+	return config["tools"].([]interface{})[0].(map[string]interface{})
+}
+
+func TestDeprecatedTools(t *testing.T) {
+	config := testConfig()
+	firstTool(config)["deprecated"] = true
+	firstTool(config)["deprecation_message"] = "Use inventory_find_host instead"
+
+	provider, err := NewProviderFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	manual := utcp.NewManual()
+	for _, tool := range provider.GetTools() {
+		manual.AddTool(tool)
+	}
+
+	getHost := manual.Tools[0]
+	if !getHost.Deprecated {
+		t.Error("Expected inventory_get_host to be deprecated")
+	}
+	if getHost.DeprecationMessage != "Use inventory_find_host instead" {
+		t.Errorf("Expected deprecation message, got %q", getHost.DeprecationMessage)
+	}
+
+	filtered := manual.WithoutDeprecated()
+	if len(filtered.Tools) != 1 {
+		t.Fatalf("Expected 1 tool after filtering, got %d", len(filtered.Tools))
+	}
+	if filtered.Tools[0].Name != "inventory_create_host" {
+		t.Errorf("Expected inventory_create_host to remain, got %s", filtered.Tools[0].Name)
+	}
+}
@@ -0,0 +1,176 @@
+package testrail
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rh-utcp/rh-utcp/internal/providers"
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// Provider represents a TestRail provider
+type Provider struct {
+	providers.BaseProvider
+	Username string
+	Password string
+}
+
+// NewProvider creates a new TestRail provider
+func NewProvider(baseURL, username, password string) *Provider {
+	return &Provider{
+		BaseProvider: providers.BaseProvider{
+			Type:    "testrail",
+			Enabled: true,
+			BaseURL: baseURL,
+		},
+		Username: username,
+		Password: password,
+	}
+}
+
+// NewProviderFromConfig creates a new TestRail provider from configuration
+func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, error) {
+	name, _ := config["name"].(string)
+	baseURL, _ := config["base_url"].(string)
+	username, _ := config["username"].(string)
+	password, _ := config["password"].(string)
+	enabled, _ := config["enabled"].(bool)
+
+	if baseURL == "" {
+		return nil, fmt.Errorf("base_url is required")
+	}
+
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("username and password are required for TestRail provider")
+	}
+
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewProvider(baseURL, username, password)
+	provider.Name = name
+	provider.Enabled = enabled
+	provider.Headers = headers
+
+	return provider, nil
+}
+
+// RequiredEnv returns the environment variables referenced by TestRail tools
+func (p *Provider) RequiredEnv() []string {
+	return []string{"TESTRAIL_USERNAME", "TESTRAIL_PASSWORD"}
+}
+
+// apiURL returns the URL for a TestRail API v2 method.
+// TestRail routes API calls through the query string of index.php.
+func (p *Provider) apiURL(method string) string {
+	return fmt.Sprintf("%s/index.php?/api/v2/%s", p.BaseURL, method)
+}
+
+// GetTools returns all available TestRail tools
+func (p *Provider) GetTools() []utcp.Tool {
+	tools := []utcp.Tool{}
+
+	// Get runs tool
+	tools = append(tools, utcp.Tool{
+		Name:        "testrail_get_runs",
+		Description: "List test runs for a TestRail project",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"project_id": {
+					Type:        "integer",
+					Description: "Project ID",
+				},
+				"is_completed": {
+					Type:        "boolean",
+					Description: "Filter by completed (true) or active (false) runs",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Maximum number of runs to return",
+					Default:     250,
+				},
+			},
+			Required: []string{"project_id"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Test runs with pass/fail counts",
+		},
+		Tags: []string{"testrail", "runs", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"testrail_get_runs",
+			p.apiURL("get_runs/${project_id}"),
+			"GET",
+			utcp.BasicAuth("TESTRAIL_USERNAME", "TESTRAIL_PASSWORD"),
+		),
+	})
+
+	// Get results tool
+	tools = append(tools, utcp.Tool{
+		Name:        "testrail_get_results",
+		Description: "Get test results for a TestRail run",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"run_id": {
+					Type:        "integer",
+					Description: "Test run ID",
+				},
+				"status_id": {
+					Type:        "string",
+					Description: "Comma-separated list of status IDs to filter by (e.g., '5' for failed)",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Maximum number of results to return",
+					Default:     250,
+				},
+			},
+			Required: []string{"run_id"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Test results with status, comment, and elapsed time",
+		},
+		Tags: []string{"testrail", "results", "list"},
+		ToolProvider: utcp.HTTPProvider(
+			"testrail_get_results",
+			p.apiURL("get_results_for_run/${run_id}"),
+			"GET",
+			utcp.BasicAuth("TESTRAIL_USERNAME", "TESTRAIL_PASSWORD"),
+		),
+	})
+
+	// Get case tool
+	tools = append(tools, utcp.Tool{
+		Name:        "testrail_get_case",
+		Description: "Get details of a TestRail test case",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"case_id": {
+					Type:        "integer",
+					Description: "Test case ID",
+				},
+			},
+			Required: []string{"case_id"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Test case details including steps and expected results",
+		},
+		Tags: []string{"testrail", "case", "get"},
+		ToolProvider: utcp.HTTPProvider(
+			"testrail_get_case",
+			p.apiURL("get_case/${case_id}"),
+			"GET",
+			utcp.BasicAuth("TESTRAIL_USERNAME", "TESTRAIL_PASSWORD"),
+			utcp.WithTimeout(5*time.Second),
+		),
+	})
+
+	return utcp.WithInputLocations(utcp.WithDefaultRetry(utcp.ApplyHeaders(tools, p.Headers)))
+}
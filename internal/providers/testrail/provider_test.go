@@ -0,0 +1,136 @@
+package testrail
+
+import (
+	"testing"
+)
+
+func TestNewProvider(t *testing.T) {
+	provider := NewProvider("https://testrail.example.com", "qa@example.com", "secret")
+
+	if provider == nil {
+		t.Fatal("NewProvider returned nil")
+	}
+
+	if provider.BaseURL != "https://testrail.example.com" {
+		t.Errorf("Expected BaseURL https://testrail.example.com, got %s", provider.BaseURL)
+	}
+
+	if provider.GetType() != "testrail" {
+		t.Errorf("Expected type 'testrail', got %s", provider.GetType())
+	}
+}
+
+func TestNewProviderFromConfig(t *testing.T) {
+	_, err := NewProviderFromConfig(map[string]interface{}{
+		"base_url": "https://testrail.example.com",
+	})
+	if err == nil {
+		t.Error("Expected error for missing credentials, got nil")
+	}
+
+	_, err = NewProviderFromConfig(map[string]interface{}{
+		"username": "qa@example.com",
+		"password": "secret",
+	})
+	if err == nil {
+		t.Error("Expected error for missing base_url, got nil")
+	}
+
+	provider, err := NewProviderFromConfig(map[string]interface{}{
+		"name":     "testrail",
+		"enabled":  true,
+		"base_url": "https://testrail.example.com",
+		"username": "qa@example.com",
+		"password": "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewProviderFromConfig failed: %v", err)
+	}
+
+	if provider.GetName() != "testrail" || !provider.IsEnabled() {
+		t.Errorf("Unexpected provider name/enabled: %s/%v", provider.GetName(), provider.IsEnabled())
+	}
+}
+
+func TestRequiredEnv(t *testing.T) {
+	provider := NewProvider("https://testrail.example.com", "qa@example.com", "secret")
+
+	required := provider.RequiredEnv()
+	if len(required) != 2 || required[0] != "TESTRAIL_USERNAME" || required[1] != "TESTRAIL_PASSWORD" {
+		t.Errorf("Expected TESTRAIL_USERNAME and TESTRAIL_PASSWORD, got %v", required)
+	}
+}
+
+func TestGetTools(t *testing.T) {
+	provider := NewProvider("https://testrail.example.com", "qa@example.com", "secret")
+	tools := provider.GetTools()
+
+	expected := map[string]struct {
+		url      string
+		required string
+	}{
+		"testrail_get_runs": {
+			url:      "https://testrail.example.com/index.php?/api/v2/get_runs/${project_id}",
+			required: "project_id",
+		},
+		"testrail_get_results": {
+			url:      "https://testrail.example.com/index.php?/api/v2/get_results_for_run/${run_id}",
+			required: "run_id",
+		},
+		"testrail_get_case": {
+			url:      "https://testrail.example.com/index.php?/api/v2/get_case/${case_id}",
+			required: "case_id",
+		},
+	}
+
+	if len(tools) != len(expected) {
+		t.Fatalf("Expected %d tools, got %d", len(expected), len(tools))
+	}
+
+	for _, tool := range tools {
+		want, exists := expected[tool.Name]
+		if !exists {
+			t.Errorf("Unexpected tool: %s", tool.Name)
+			continue
+		}
+
+		if tool.ToolProvider["url"] != want.url {
+			t.Errorf("Tool %s: expected url %s, got %v", tool.Name, want.url, tool.ToolProvider["url"])
+		}
+
+		if tool.ToolProvider["http_method"] != "GET" {
+			t.Errorf("Tool %s: expected http_method GET, got %v", tool.Name, tool.ToolProvider["http_method"])
+		}
+
+		if len(tool.Inputs.Required) != 1 || tool.Inputs.Required[0] != want.required {
+			t.Errorf("Tool %s: expected '%s' to be the only required field, got %v", tool.Name, want.required, tool.Inputs.Required)
+		}
+
+		if _, exists := tool.Inputs.Properties[want.required]; !exists {
+			t.Errorf("Tool %s: missing property %s", tool.Name, want.required)
+		}
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	provider := NewProvider("https://testrail.example.com", "qa@example.com", "secret")
+
+	for _, tool := range provider.GetTools() {
+		auth, ok := tool.ToolProvider["auth"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Tool %s: auth is not a map", tool.Name)
+		}
+
+		if auth["auth_type"] != "basic" {
+			t.Errorf("Tool %s: expected auth_type 'basic', got %v", tool.Name, auth["auth_type"])
+		}
+
+		if auth["username"] != "$TESTRAIL_USERNAME" {
+			t.Errorf("Tool %s: expected username '$TESTRAIL_USERNAME', got %v", tool.Name, auth["username"])
+		}
+
+		if auth["password"] != "$TESTRAIL_PASSWORD" {
+			t.Errorf("Tool %s: expected password '$TESTRAIL_PASSWORD', got %v", tool.Name, auth["password"])
+		}
+	}
+}
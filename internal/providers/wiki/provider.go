@@ -2,6 +2,7 @@ package wiki
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/rh-utcp/rh-utcp/internal/providers"
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
@@ -40,13 +41,24 @@ func NewProviderFromConfig(config map[string]interface{}) (providers.Provider, e
 		return nil, fmt.Errorf("api_key is required for Wiki provider")
 	}
 
+	headers, err := providers.HeadersFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	provider := NewProvider(baseURL, apiKey)
 	provider.Name = name
 	provider.Enabled = enabled
+	provider.Headers = headers
 
 	return provider, nil
 }
 
+// RequiredEnv returns the environment variables referenced by Wiki tools
+func (p *Provider) RequiredEnv() []string {
+	return []string{"WIKI_API_KEY"}
+}
+
 // GetTools returns all available Wiki tools
 func (p *Provider) GetTools() []utcp.Tool {
 	tools := []utcp.Tool{}
@@ -85,8 +97,26 @@ func (p *Provider) GetTools() []utcp.Tool {
 		},
 		Tags:                []string{"wiki", "search", "confluence"},
 		AverageResponseSize: 500,
+		Examples: []utcp.ToolExample{
+			{
+				Name: "Search a space for runbooks",
+				Input: map[string]interface{}{
+					"query": "runbook",
+					"space": "OPS",
+					"limit": 5,
+				},
+				Output: map[string]interface{}{
+					"results": []map[string]interface{}{
+						{"id": "123456", "type": "page", "title": "Database failover runbook"},
+					},
+					"start": 0,
+					"limit": 5,
+					"size":  1,
+				},
+			},
+		},
 		ToolProvider: utcp.HTTPProvider(
-			"wiki_search",
+			"wiki_search_pages",
 			fmt.Sprintf("%s/rest/api/content/search", p.BaseURL),
 			"GET",
 			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
@@ -130,6 +160,7 @@ func (p *Provider) GetTools() []utcp.Tool {
 			fmt.Sprintf("%s/rest/api/content/${pageId}", p.BaseURL),
 			"GET",
 			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
+			utcp.WithTimeout(5*time.Second),
 		),
 	})
 
@@ -157,7 +188,8 @@ func (p *Provider) GetTools() []utcp.Tool {
 					Description: "Parent page ID (optional)",
 				},
 			},
-			Required: []string{"title", "spaceKey", "content"},
+			Required:             []string{"title", "spaceKey", "content"},
+			AdditionalProperties: utcp.Bool(false),
 		},
 		Outputs: utcp.Schema{
 			Type:        "object",
@@ -200,7 +232,8 @@ func (p *Provider) GetTools() []utcp.Tool {
 					Description: "Version message/comment",
 				},
 			},
-			Required: []string{"pageId", "title", "content", "version"},
+			Required:             []string{"pageId", "title", "content", "version"},
+			AdditionalProperties: utcp.Bool(false),
 		},
 		Outputs: utcp.Schema{
 			Type:        "object",
@@ -215,6 +248,72 @@ func (p *Provider) GetTools() []utcp.Tool {
 		),
 	})
 
+	// Delete page tool
+	tools = append(tools, utcp.Tool{
+		Name:        "wiki_delete_page",
+		Description: "Delete a wiki page (moves it to the space trash)",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"pageId": {
+					Type:        "string",
+					Description: "Page ID to delete",
+				},
+			},
+			Required: []string{"pageId"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Empty response on success",
+		},
+		Tags: []string{"wiki", "delete", "page"},
+		ToolProvider: utcp.HTTPProvider(
+			"wiki_delete_page",
+			fmt.Sprintf("%s/rest/api/content/${pageId}", p.BaseURL),
+			"DELETE",
+			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
+		),
+	})
+
+	// Move page tool
+	tools = append(tools, utcp.Tool{
+		Name:        "wiki_move_page",
+		Description: "Move a wiki page under a new parent page by updating its ancestors",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"pageId": {
+					Type:        "string",
+					Description: "Page ID to move",
+				},
+				"parentId": {
+					Type:        "string",
+					Description: "ID of the new parent page (sent as ancestors: [{id: parentId}])",
+				},
+				"title": {
+					Type:        "string",
+					Description: "Current page title (required by the update API)",
+				},
+				"version": {
+					Type:        "integer",
+					Description: "Next version number (current version + 1)",
+				},
+			},
+			Required: []string{"pageId", "parentId", "title", "version"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Updated page details including new ancestors",
+		},
+		Tags: []string{"wiki", "move", "page"},
+		ToolProvider: utcp.HTTPProvider(
+			"wiki_move_page",
+			fmt.Sprintf("%s/rest/api/content/${pageId}", p.BaseURL),
+			"PUT",
+			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
+		),
+	})
+
 	// List spaces tool
 	tools = append(tools, utcp.Tool{
 		Name:        "wiki_list_spaces",
@@ -313,12 +412,14 @@ func (p *Provider) GetTools() []utcp.Tool {
 			Type:        "object",
 			Description: "Export URL or binary content",
 		},
-		Tags: []string{"wiki", "export", "download"},
+		Tags:             []string{"wiki", "export", "download"},
+		AverageLatencyMs: 15000,
 		ToolProvider: utcp.HTTPProvider(
 			"wiki_export_page",
 			fmt.Sprintf("%s/rest/api/content/${pageId}/export/${format}", p.BaseURL),
 			"GET",
 			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
+			utcp.WithTimeout(60*time.Second),
 		),
 	})
 
@@ -347,12 +448,71 @@ func (p *Provider) GetTools() []utcp.Tool {
 		},
 		Tags: []string{"wiki", "history", "versions"},
 		ToolProvider: utcp.HTTPProvider(
-			"wiki_get_history",
+			"wiki_get_page_history",
 			fmt.Sprintf("%s/rest/api/content/${pageId}/version", p.BaseURL),
 			"GET",
 			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
 		),
 	})
 
-	return tools
+	// Get page labels tool
+	tools = append(tools, utcp.Tool{
+		Name:        "wiki_get_page_labels",
+		Description: "Get the labels attached to a wiki page",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"pageId": {
+					Type:        "string",
+					Description: "Page ID",
+				},
+			},
+			Required: []string{"pageId"},
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "Labels with their prefix and name",
+		},
+		Tags: []string{"wiki", "labels", "page"},
+		ToolProvider: utcp.HTTPProvider(
+			"wiki_get_page_labels",
+			fmt.Sprintf("%s/rest/api/content/${pageId}/label", p.BaseURL),
+			"GET",
+			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
+		),
+	})
+
+	// Add page label tool
+	tools = append(tools, utcp.Tool{
+		Name:        "wiki_add_page_label",
+		Description: "Add labels to a wiki page",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"pageId": {
+					Type:        "string",
+					Description: "Page ID",
+				},
+				"labels": {
+					Type:        "array",
+					Description: "Labels to add, each an object with a prefix (usually \"global\") and a name",
+				},
+			},
+			Required:             []string{"pageId", "labels"},
+			AdditionalProperties: utcp.Bool(false),
+		},
+		Outputs: utcp.Schema{
+			Type:        "object",
+			Description: "All labels on the page after the change",
+		},
+		Tags: []string{"wiki", "labels", "page", "add"},
+		ToolProvider: utcp.HTTPProvider(
+			"wiki_add_page_label",
+			fmt.Sprintf("%s/rest/api/content/${pageId}/label", p.BaseURL),
+			"POST",
+			utcp.APIKeyAuth("WIKI_API_KEY", "Authorization"),
+		),
+	})
+
+	return utcp.WithInputLocations(utcp.WithDefaultRetry(utcp.ApplyHeaders(tools, p.Headers)))
 }
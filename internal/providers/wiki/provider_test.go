@@ -1,6 +1,8 @@
 package wiki
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/rh-utcp/rh-utcp/pkg/utcp"
@@ -25,6 +27,23 @@ func TestNewProvider(t *testing.T) {
 	}
 }
 
+func TestRequiredEnv(t *testing.T) {
+	provider := NewProvider("https://wiki.example.com", "test-key")
+
+	expected := []string{"WIKI_API_KEY"}
+	required := provider.RequiredEnv()
+
+	if len(required) != len(expected) {
+		t.Fatalf("Expected %d required env vars, got %d", len(expected), len(required))
+	}
+
+	for i, name := range expected {
+		if required[i] != name {
+			t.Errorf("Expected required env var %s, got %s", name, required[i])
+		}
+	}
+}
+
 func TestGetTools(t *testing.T) {
 	provider := NewProvider("https://wiki.example.com", "test-key")
 	tools := provider.GetTools()
@@ -39,10 +58,14 @@ func TestGetTools(t *testing.T) {
 		"wiki_get_page":         false,
 		"wiki_create_page":      false,
 		"wiki_update_page":      false,
+		"wiki_delete_page":      false,
+		"wiki_move_page":        false,
 		"wiki_list_spaces":      false,
 		"wiki_get_attachments":  false,
 		"wiki_export_page":      false,
 		"wiki_get_page_history": false,
+		"wiki_get_page_labels":  false,
+		"wiki_add_page_label":   false,
 	}
 
 	// Check all expected tools are present
@@ -175,6 +198,15 @@ func TestWikiGetPageTool(t *testing.T) {
 	if getTool.AverageResponseSize != 1000 {
 		t.Errorf("Expected average response size 1000, got %d", getTool.AverageResponseSize)
 	}
+
+	// Check latency hint is omitted when unset
+	data, err := json.Marshal(getTool)
+	if err != nil {
+		t.Fatalf("Failed to marshal tool: %v", err)
+	}
+	if strings.Contains(string(data), "average_latency_ms") {
+		t.Errorf("Expected average_latency_ms to be omitted, got %s", data)
+	}
 }
 
 func TestWikiCreatePageTool(t *testing.T) {
@@ -216,6 +248,87 @@ func TestWikiCreatePageTool(t *testing.T) {
 	if toolProvider["http_method"] != "POST" {
 		t.Errorf("Expected http_method 'POST', got %v", toolProvider["http_method"])
 	}
+
+	// Create rejects undeclared inputs
+	if ap := createTool.Inputs.AdditionalProperties; ap == nil || *ap {
+		t.Errorf("Expected additionalProperties false, got %v", ap)
+	}
+}
+
+func TestWikiDeletePageTool(t *testing.T) {
+	provider := NewProvider("https://wiki.example.com", "test-key")
+
+	var deleteTool *utcp.Tool
+	for _, tool := range provider.GetTools() {
+		if tool.Name == "wiki_delete_page" {
+			deleteTool = &tool
+			break
+		}
+	}
+
+	if deleteTool == nil {
+		t.Fatal("wiki_delete_page tool not found")
+	}
+
+	if deleteTool.ToolProvider["http_method"] != "DELETE" {
+		t.Errorf("Expected http_method 'DELETE', got %v", deleteTool.ToolProvider["http_method"])
+	}
+
+	if url := deleteTool.ToolProvider["url"]; url != "https://wiki.example.com/rest/api/content/${pageId}" {
+		t.Errorf("Unexpected URL: %v", url)
+	}
+
+	if len(deleteTool.Inputs.Required) != 1 || deleteTool.Inputs.Required[0] != "pageId" {
+		t.Error("Expected 'pageId' to be the only required field")
+	}
+}
+
+func TestWikiLabelTools(t *testing.T) {
+	provider := NewProvider("https://wiki.example.com", "test-key")
+
+	tools := make(map[string]utcp.Tool)
+	for _, tool := range provider.GetTools() {
+		tools[tool.Name] = tool
+	}
+
+	getLabels, exists := tools["wiki_get_page_labels"]
+	if !exists {
+		t.Fatal("wiki_get_page_labels tool not found")
+	}
+
+	if getLabels.ToolProvider["http_method"] != "GET" {
+		t.Errorf("Expected http_method 'GET', got %v", getLabels.ToolProvider["http_method"])
+	}
+
+	addLabel, exists := tools["wiki_add_page_label"]
+	if !exists {
+		t.Fatal("wiki_add_page_label tool not found")
+	}
+
+	if addLabel.ToolProvider["http_method"] != "POST" {
+		t.Errorf("Expected http_method 'POST', got %v", addLabel.ToolProvider["http_method"])
+	}
+
+	expectedURL := "https://wiki.example.com/rest/api/content/${pageId}/label"
+	for _, tool := range []utcp.Tool{getLabels, addLabel} {
+		if tool.ToolProvider["url"] != expectedURL {
+			t.Errorf("%s: expected URL %s, got %v", tool.Name, expectedURL, tool.ToolProvider["url"])
+		}
+
+		auth, _ := tool.ToolProvider["auth"].(map[string]interface{})
+		if auth["auth_type"] != "api_key" {
+			t.Errorf("%s: expected api_key auth, got %v", tool.Name, auth["auth_type"])
+		}
+	}
+
+	required := strings.Join(addLabel.Inputs.Required, ",")
+	if required != "pageId,labels" {
+		t.Errorf("Expected required fields pageId,labels, got %s", required)
+	}
+
+	if addLabel.Inputs.Properties["labels"].Type != "array" {
+		t.Errorf("Expected labels to be an array, got %s", addLabel.Inputs.Properties["labels"].Type)
+	}
 }
 
 func TestWikiListSpacesTool(t *testing.T) {
@@ -288,12 +401,22 @@ func TestWikiExportPageTool(t *testing.T) {
 		t.Errorf("Expected default format 'pdf', got %v", formatProp.Default)
 	}
 
+	// Check latency hint
+	if exportTool.AverageLatencyMs != 15000 {
+		t.Errorf("Expected average latency 15000ms, got %d", exportTool.AverageLatencyMs)
+	}
+
 	// Check URL pattern
 	toolProvider := exportTool.ToolProvider
 	expectedURL := "https://wiki.example.com/rest/api/content/${pageId}/export/${format}"
 	if toolProvider["url"] != expectedURL {
 		t.Errorf("Expected URL %s, got %v", expectedURL, toolProvider["url"])
 	}
+
+	// Exports are slow, so clients should wait longer than for other tools
+	if toolProvider["timeout_ms"] != int64(60000) {
+		t.Errorf("Expected timeout_ms 60000, got %v", toolProvider["timeout_ms"])
+	}
 }
 
 func TestAllToolsHaveValidProviders(t *testing.T) {
@@ -318,11 +441,22 @@ func TestAllToolsHaveValidProviders(t *testing.T) {
 			t.Errorf("Tool %s has empty outputs type", tool.Name)
 		}
 
+		// Check enum defaults are valid options
+		for name, property := range tool.Inputs.Properties {
+			if err := property.Validate(); err != nil {
+				t.Errorf("Tool %s input %s: %v", tool.Name, name, err)
+			}
+		}
+
 		// Check provider configuration
 		if tool.ToolProvider == nil {
 			t.Errorf("Tool %s has nil ToolProvider", tool.Name)
 		}
 
+		if id := tool.ToolProvider["provider_id"]; id != tool.Name {
+			t.Errorf("Tool %s has mismatched provider_id %v", tool.Name, id)
+		}
+
 		providerType, ok := tool.ToolProvider["provider_type"].(string)
 		if !ok || providerType != "http" {
 			t.Errorf("Tool %s has invalid provider_type", tool.Name)
@@ -334,7 +468,7 @@ func TestAllToolsHaveValidProviders(t *testing.T) {
 		}
 
 		method, ok := tool.ToolProvider["http_method"].(string)
-		if !ok || (method != "GET" && method != "POST" && method != "PUT") {
+		if !ok || !utcp.ValidHTTPMethod(method) {
 			t.Errorf("Tool %s has invalid HTTP method: %s", tool.Name, method)
 		}
 
@@ -0,0 +1,256 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode controls whether a Recorder talks to the real service or replays fixtures
+type Mode int
+
+const (
+	// ModeAuto replays when the fixture file exists and records otherwise
+	ModeAuto Mode = iota
+	// ModeRecord always forwards requests and overwrites the fixture file
+	ModeRecord
+	// ModeReplay only serves responses from the fixture file
+	ModeReplay
+)
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest holds the parts of a request used for matching
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse holds the parts of a response needed to rebuild it
+type RecordedResponse struct {
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       string              `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records interactions to a JSON
+// fixture on first run and replays them on subsequent runs
+type Recorder struct {
+	mu           sync.Mutex
+	path         string
+	mode         Mode
+	transport    http.RoundTripper
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder creates a new recorder backed by the fixture at path.
+// In ModeAuto the mode is resolved to ModeReplay if the fixture exists
+// and to ModeRecord otherwise. A nil transport uses http.DefaultTransport.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+	}
+
+	if r.mode == ModeAuto {
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Mode returns the resolved mode of the recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client that uses the recorder as its transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns a copy of the interactions held by the recorder
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interactions := make([]Interaction, len(r.interactions))
+	copy(interactions, r.interactions)
+	return interactions
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   body,
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, recorded)
+}
+
+// Save writes the recorded interactions to the fixture file.
+// It is a no-op in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture %s: %w", r.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture %s: %w", r.path, err)
+	}
+
+	return nil
+}
+
+// record forwards the request to the real transport and stores the result
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(respBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return buildResponse(req, interaction.Response), nil
+}
+
+// replay serves the first unused interaction matching the request
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+		return buildResponse(req, interaction.Response), nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s in %s", recorded.Method, recorded.URL, r.path)
+}
+
+// load reads interactions from the fixture file
+func (r *Recorder) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %s: %w", r.path, err)
+	}
+
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return fmt.Errorf("failed to decode fixture %s: %w", r.path, err)
+	}
+
+	r.used = make([]bool, len(r.interactions))
+	return nil
+}
+
+// matches reports whether a recorded request matches an incoming one
+func matches(recorded, incoming RecordedRequest) bool {
+	return recorded.Method == incoming.Method &&
+		recorded.URL == incoming.URL &&
+		recorded.Body == incoming.Body
+}
+
+// readRequestBody reads the request body and restores it for the transport
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	return string(data), nil
+}
+
+// buildResponse creates an http.Response from a recorded response
+func buildResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	header := http.Header{}
+	for k, v := range recorded.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
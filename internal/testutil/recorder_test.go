@@ -0,0 +1,171 @@
+package testutil
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func newGitLabServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		if r.URL.Path != "/api/v4/user" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"username":"testuser"}`))
+	}))
+}
+
+func getUser(t *testing.T, client *http.Client, baseURL string) map[string]interface{} {
+	t.Helper()
+
+	req, _ := http.NewRequest("GET", baseURL+"/api/v4/user", nil)
+	req.Header.Set("PRIVATE-TOKEN", "test-token")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	var user map[string]interface{}
+	if err := json.Unmarshal(body, &user); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	return user
+}
+
+func TestRecordThenReplayGitLabUser(t *testing.T) {
+	var calls int32
+	server := newGitLabServer(t, &calls)
+	baseURL := server.URL
+	fixture := filepath.Join(t.TempDir(), "fixtures", "gitlab_user.json")
+
+	// First run records against the live server
+	recorder, err := NewRecorder(fixture, ModeAuto, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	if recorder.Mode() != ModeRecord {
+		t.Fatalf("Expected record mode without fixture, got %v", recorder.Mode())
+	}
+
+	user := getUser(t, recorder.Client(), baseURL)
+	if user["username"] != "testuser" {
+		t.Errorf("Expected username 'testuser', got %v", user["username"])
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if _, err := os.Stat(fixture); err != nil {
+		t.Fatalf("Fixture was not written: %v", err)
+	}
+
+	// Second run replays without the server
+	server.Close()
+
+	replayer, err := NewRecorder(fixture, ModeAuto, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	if replayer.Mode() != ModeReplay {
+		t.Fatalf("Expected replay mode with fixture, got %v", replayer.Mode())
+	}
+
+	user = getUser(t, replayer.Client(), baseURL)
+	if user["id"] != float64(1) {
+		t.Errorf("Expected id 1, got %v", user["id"])
+	}
+
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected 1 call to the live server, got %d", atomic.LoadInt32(&calls))
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(fixture, []byte("[]"), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	recorder, err := NewRecorder(fixture, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	_, err = recorder.Client().Get("http://gitlab.example.com/api/v4/user")
+	if err == nil {
+		t.Error("Expected error for unrecorded request, got nil")
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	if err == nil {
+		t.Error("Expected error for missing fixture in replay mode, got nil")
+	}
+}
+
+func TestReplayConsumesInteractionsInOrder(t *testing.T) {
+	interactions := []Interaction{
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "http://example.com/a"},
+			Response: RecordedResponse{StatusCode: 200, Body: "first"},
+		},
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "http://example.com/a"},
+			Response: RecordedResponse{StatusCode: 404, Body: "second"},
+		},
+	}
+
+	data, _ := json.Marshal(interactions)
+	fixture := filepath.Join(t.TempDir(), "ordered.json")
+	if err := os.WriteFile(fixture, data, 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	recorder, err := NewRecorder(fixture, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	client := recorder.Client()
+	for _, expected := range []int{200, 404} {
+		resp, err := client.Get("http://example.com/a")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != expected {
+			t.Errorf("Expected status %d, got %d", expected, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package circuit provides a consecutive-failure circuit breaker used to stop
+// probing upstreams that are known to be down.
+package circuit
+
+import (
+	"sync"
+	"time"
+)
+
+// State describes the state of a circuit breaker
+type State string
+
+const (
+	// Closed allows calls through
+	Closed State = "closed"
+	// Open rejects calls until the cooldown elapses
+	Open State = "open"
+	// HalfOpen allows a single probe call through
+	HalfOpen State = "half_open"
+)
+
+// Breaker tracks consecutive failures so that an unhealthy upstream is not
+// called on every request
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+}
+
+// New creates a closed breaker that opens after threshold consecutive
+// failures and probes again after cooldown
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return NewWithClock(threshold, cooldown, time.Now)
+}
+
+// NewWithClock creates a breaker that reads the current time from now
+func NewWithClock(threshold int, cooldown time.Duration, now func() time.Time) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       now,
+		state:     Closed,
+	}
+}
+
+// Allow reports whether a call may be attempted. An open breaker moves to
+// half-open once its cooldown has elapsed and lets one probe through.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = HalfOpen
+		return true
+	case HalfOpen:
+		// A probe is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets the failure count
+func (b *Breaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = Closed
+	b.failures = 0
+}
+
+// RecordFailure counts a failure, opening the breaker when the threshold is
+// reached or when a half-open probe fails
+func (b *Breaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.state = Open
+		b.openedAt = b.now()
+	}
+}
+
+// State returns the current breaker state
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Failures returns the number of consecutive failures recorded
+func (b *Breaker) Failures() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures
+}
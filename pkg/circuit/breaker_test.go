@@ -0,0 +1,84 @@
+package circuit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	breaker := New(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		breaker.RecordFailure()
+	}
+
+	if breaker.State() != Closed {
+		t.Errorf("Expected state %s, got %s", Closed, breaker.State())
+	}
+
+	breaker.RecordFailure()
+
+	if breaker.State() != Open {
+		t.Errorf("Expected state %s, got %s", Open, breaker.State())
+	}
+
+	if breaker.Allow() {
+		t.Error("Expected open breaker to reject calls")
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	breaker := New(2, time.Minute)
+
+	breaker.RecordFailure()
+	breaker.RecordSuccess()
+	breaker.RecordFailure()
+
+	if breaker.State() != Closed {
+		t.Errorf("Expected non-consecutive failures to keep the breaker %s, got %s", Closed, breaker.State())
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	breaker := NewWithClock(1, time.Minute, func() time.Time { return now })
+
+	breaker.RecordFailure()
+
+	now = now.Add(30 * time.Second)
+	if breaker.Allow() {
+		t.Fatal("Expected calls to be rejected during the cooldown")
+	}
+
+	now = now.Add(30 * time.Second)
+	if !breaker.Allow() {
+		t.Fatal("Expected a probe to be allowed after the cooldown")
+	}
+
+	if breaker.State() != HalfOpen {
+		t.Errorf("Expected state %s, got %s", HalfOpen, breaker.State())
+	}
+
+	if breaker.Allow() {
+		t.Error("Expected only one probe while half-open")
+	}
+
+	// A failed probe re-opens the breaker
+	breaker.RecordFailure()
+	if breaker.State() != Open {
+		t.Errorf("Expected state %s, got %s", Open, breaker.State())
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	breaker.Allow()
+	breaker.RecordSuccess()
+
+	if breaker.State() != Closed {
+		t.Errorf("Expected state %s, got %s", Closed, breaker.State())
+	}
+
+	if breaker.Failures() != 0 {
+		t.Errorf("Expected 0 failures, got %d", breaker.Failures())
+	}
+}
@@ -1,10 +1,62 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"runtime"
+	"sync/atomic"
 )
 
+const (
+	// DefaultMaxStackDepth is the number of frames captured by default
+	DefaultMaxStackDepth = 10
+
+	// MaxStackDepthCeiling bounds stack capture when the depth is unlimited
+	MaxStackDepthCeiling = 128
+)
+
+// maxStackDepth is the number of frames captured for new errors
+var maxStackDepth atomic.Int32
+
+// captureStacks reports whether new errors capture a stack trace
+var captureStacks atomic.Bool
+
+func init() {
+	maxStackDepth.Store(DefaultMaxStackDepth)
+	captureStacks.Store(true)
+}
+
+// SetCaptureStack turns stack capture for new errors on or off. It is on by
+// default; turning it off saves the runtime.Caller walk for deployments that
+// create many errors on hot paths, such as request validation. Errors
+// created while it is off have a nil Stack.
+func SetCaptureStack(enabled bool) {
+	captureStacks.Store(enabled)
+}
+
+// CaptureStack reports whether new errors capture a stack trace
+func CaptureStack() bool {
+	return captureStacks.Load()
+}
+
+// SetMaxStackDepth sets how many stack frames are captured when an error is
+// created. Zero means unlimited, up to MaxStackDepthCeiling; negative values
+// restore the default.
+func SetMaxStackDepth(n int) {
+	if n < 0 {
+		n = DefaultMaxStackDepth
+	}
+	if n == 0 || n > MaxStackDepthCeiling {
+		n = MaxStackDepthCeiling
+	}
+	maxStackDepth.Store(int32(n))
+}
+
+// MaxStackDepth returns the number of stack frames captured for new errors
+func MaxStackDepth() int {
+	return int(maxStackDepth.Load())
+}
+
 // ErrorType represents the type of error
 type ErrorType string
 
@@ -43,9 +95,30 @@ type Error struct {
 
 // StackFrame represents a single frame in a stack trace
 type StackFrame struct {
-	Function string
-	File     string
-	Line     int
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// jsonError is the wire representation of an Error
+type jsonError struct {
+	Type       ErrorType              `json:"type,omitempty"`
+	Message    string                 `json:"message"`
+	Provider   string                 `json:"provider,omitempty"`
+	Operation  string                 `json:"operation,omitempty"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	Context    map[string]interface{} `json:"context,omitempty"`
+	Stack      []StackFrame           `json:"stack,omitempty"`
+	Cause      *jsonError             `json:"cause,omitempty"`
+}
+
+// remoteError is a cause reconstructed from JSON whose original type is unknown
+type remoteError struct {
+	message string
+}
+
+func (r *remoteError) Error() string {
+	return r.message
 }
 
 // Error implements the error interface
@@ -61,6 +134,74 @@ func (e *Error) Unwrap() error {
 	return e.Cause
 }
 
+// MarshalJSON implements json.Marshaler. Causes that are not *Error are
+// serialized as a nested message only.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONError(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A nested cause without a type
+// is restored as a plain error carrying its message.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+
+	*e = *fromJSONError(&je)
+	return nil
+}
+
+// toJSONError converts an error chain to its wire representation
+func toJSONError(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		return &jsonError{Message: err.Error()}
+	}
+
+	return &jsonError{
+		Type:       e.Type,
+		Message:    e.Message,
+		Provider:   e.Provider,
+		Operation:  e.Operation,
+		StatusCode: e.StatusCode,
+		Context:    e.Context,
+		Stack:      e.Stack,
+		Cause:      toJSONError(e.Cause),
+	}
+}
+
+// fromJSONError rebuilds an Error from its wire representation
+func fromJSONError(je *jsonError) *Error {
+	e := &Error{
+		Type:       je.Type,
+		Message:    je.Message,
+		Provider:   je.Provider,
+		Operation:  je.Operation,
+		StatusCode: je.StatusCode,
+		Stack:      je.Stack,
+		Context:    je.Context,
+	}
+
+	if e.Context == nil {
+		e.Context = make(map[string]interface{})
+	}
+
+	if je.Cause != nil {
+		if je.Cause.Type == "" {
+			e.Cause = &remoteError{message: je.Cause.Message}
+		} else {
+			e.Cause = fromJSONError(je.Cause)
+		}
+	}
+
+	return e
+}
+
 // WithContext adds context to the error
 func (e *Error) WithContext(key string, value interface{}) *Error {
 	if e.Context == nil {
@@ -168,9 +309,15 @@ func ensureError(err error) *Error {
 	}
 }
 
-// captureStack captures the current stack trace
+// captureStack captures the current stack trace, or returns nil when stack
+// capture is disabled
 func captureStack(skip int) []StackFrame {
-	var frames []StackFrame
+	if !CaptureStack() {
+		return nil
+	}
+
+	depth := MaxStackDepth()
+	frames := make([]StackFrame, 0, depth)
 
 	for i := skip; ; i++ {
 		pc, file, line, ok := runtime.Caller(i)
@@ -190,7 +337,7 @@ func captureStack(skip int) []StackFrame {
 		})
 
 		// Limit stack depth
-		if len(frames) >= 10 {
+		if len(frames) >= depth {
 			break
 		}
 	}
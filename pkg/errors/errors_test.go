@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 )
@@ -419,3 +420,198 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestJSONRoundTrip(t *testing.T) {
+	root := errors.New("connection refused")
+	inner := Wrap(root, ErrorTypeNetwork, "failed to reach upstream")
+	inner = WithProvider(inner, "jira")
+	inner = WithOperation(inner, "search")
+
+	err := Wrap(inner, ErrorTypeProvider, "search failed")
+	err.WithContext("jql", "project = PROJ")
+	err = WithStatusCode(err, 502)
+
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("Marshal failed: %v", marshalErr)
+	}
+
+	var decoded Error
+	if unmarshalErr := json.Unmarshal(data, &decoded); unmarshalErr != nil {
+		t.Fatalf("Unmarshal failed: %v", unmarshalErr)
+	}
+
+	if decoded.Type != ErrorTypeProvider {
+		t.Errorf("Expected type %s, got %s", ErrorTypeProvider, decoded.Type)
+	}
+
+	if decoded.Message != "search failed" {
+		t.Errorf("Expected message 'search failed', got %s", decoded.Message)
+	}
+
+	if decoded.Provider != "jira" || decoded.Operation != "search" {
+		t.Errorf("Expected provider/operation jira/search, got %s/%s", decoded.Provider, decoded.Operation)
+	}
+
+	if decoded.StatusCode != 502 {
+		t.Errorf("Expected status code 502, got %d", decoded.StatusCode)
+	}
+
+	if decoded.Context["jql"] != "project = PROJ" {
+		t.Errorf("Expected context jql 'project = PROJ', got %v", decoded.Context["jql"])
+	}
+
+	if len(decoded.Stack) != len(err.Stack) {
+		t.Fatalf("Expected %d stack frames, got %d", len(err.Stack), len(decoded.Stack))
+	}
+
+	if decoded.Stack[0] != err.Stack[0] {
+		t.Errorf("Expected first frame %+v, got %+v", err.Stack[0], decoded.Stack[0])
+	}
+
+	// The wrapped *Error keeps its type, the foreign root becomes a message
+	cause, ok := decoded.Cause.(*Error)
+	if !ok {
+		t.Fatalf("Expected *Error cause, got %T", decoded.Cause)
+	}
+
+	if cause.Type != ErrorTypeNetwork {
+		t.Errorf("Expected cause type %s, got %s", ErrorTypeNetwork, cause.Type)
+	}
+
+	if cause.Cause == nil || cause.Cause.Error() != "connection refused" {
+		t.Errorf("Expected root cause message 'connection refused', got %v", cause.Cause)
+	}
+
+	if decoded.Error() != err.Error() {
+		t.Errorf("Expected error string '%s', got '%s'", err.Error(), decoded.Error())
+	}
+}
+
+func TestJSONFieldNames(t *testing.T) {
+	err := WithStatusCode(New(ErrorTypeValidation, "bad input"), 400)
+
+	data, _ := json.Marshal(err)
+
+	var fields map[string]interface{}
+	if unmarshalErr := json.Unmarshal(data, &fields); unmarshalErr != nil {
+		t.Fatalf("Unmarshal failed: %v", unmarshalErr)
+	}
+
+	for _, key := range []string{"type", "message", "status_code", "stack"} {
+		if _, exists := fields[key]; !exists {
+			t.Errorf("Missing field '%s' in serialized error", key)
+		}
+	}
+
+	stack, ok := fields["stack"].([]interface{})
+	if !ok || len(stack) == 0 {
+		t.Fatal("Expected non-empty stack array")
+	}
+
+	frame, _ := stack[0].(map[string]interface{})
+	for _, key := range []string{"function", "file", "line"} {
+		if _, exists := frame[key]; !exists {
+			t.Errorf("Missing field '%s' in stack frame", key)
+		}
+	}
+}
+
+func TestSetMaxStackDepth(t *testing.T) {
+	SetMaxStackDepth(3)
+	defer SetMaxStackDepth(DefaultMaxStackDepth)
+
+	cases := map[string]*Error{
+		"New":          New(ErrorTypeInternal, "test"),
+		"Newf":         Newf(ErrorTypeInternal, "test %d", 1),
+		"Wrap":         Wrap(errors.New("cause"), ErrorTypeInternal, "test"),
+		"WithProvider": WithProvider(errors.New("cause"), "jira"),
+	}
+
+	for name, err := range cases {
+		if len(err.Stack) != 3 {
+			t.Errorf("%s: expected stack length 3, got %d", name, len(err.Stack))
+		}
+	}
+}
+
+func nestedError(depth int) *Error {
+	if depth == 0 {
+		return New(ErrorTypeProvider, "deep failure")
+	}
+	return nestedError(depth - 1)
+}
+
+func TestSetMaxStackDepthDeepCallChain(t *testing.T) {
+	if len(nestedError(30).Stack) != DefaultMaxStackDepth {
+		t.Errorf("Expected default stack length %d", DefaultMaxStackDepth)
+	}
+
+	SetMaxStackDepth(64)
+	defer SetMaxStackDepth(DefaultMaxStackDepth)
+
+	stack := nestedError(30).Stack
+	if len(stack) <= 30 {
+		t.Errorf("Expected more than 30 frames, got %d", len(stack))
+	}
+
+	SetMaxStackDepth(0)
+	if MaxStackDepth() != MaxStackDepthCeiling {
+		t.Errorf("Expected unlimited depth to use ceiling %d, got %d", MaxStackDepthCeiling, MaxStackDepth())
+	}
+
+	stack = nestedError(MaxStackDepthCeiling + 10).Stack
+	if len(stack) != MaxStackDepthCeiling {
+		t.Errorf("Expected stack capped at %d, got %d", MaxStackDepthCeiling, len(stack))
+	}
+}
+
+func TestSetCaptureStack(t *testing.T) {
+	if !CaptureStack() {
+		t.Fatal("Expected stack capture to be enabled by default")
+	}
+
+	SetCaptureStack(false)
+	defer SetCaptureStack(true)
+
+	cases := map[string]*Error{
+		"New":             New(ErrorTypeInternal, "test"),
+		"Wrap":            Wrap(errors.New("cause"), ErrorTypeInternal, "test"),
+		"ValidationError": ValidationError("bad input"),
+	}
+
+	for name, err := range cases {
+		if err.Stack != nil {
+			t.Errorf("%s: expected nil stack, got %d frames", name, len(err.Stack))
+		}
+		if GetStack(err) != nil {
+			t.Errorf("%s: expected GetStack to return nil", name)
+		}
+	}
+
+	SetCaptureStack(true)
+	if len(New(ErrorTypeInternal, "test").Stack) == 0 {
+		t.Error("Expected stack after re-enabling capture")
+	}
+}
+
+// BenchmarkNew shows the allocation cost of stack capture
+func BenchmarkNew(b *testing.B) {
+	for _, bench := range []struct {
+		name    string
+		capture bool
+	}{
+		{"stack", true},
+		{"no_stack", false},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			SetCaptureStack(bench.capture)
+			defer SetCaptureStack(true)
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = New(ErrorTypeValidation, "bad input")
+			}
+		})
+	}
+}
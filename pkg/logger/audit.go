@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry records a single access to the UTCP discovery endpoint
+type AuditEntry struct {
+	Timestamp time.Time    `json:"timestamp"`
+	ClientIP  string       `json:"client_ip"`
+	UserAgent string       `json:"user_agent"`
+	RequestID string       `json:"request_id,omitempty"`
+	Tools     int          `json:"tools"`
+	Filters   AuditFilters `json:"filters"`
+}
+
+// AuditFilters records the discovery filters that were applied to a request
+type AuditFilters struct {
+	IncludeProvider   bool `json:"include_provider"`
+	IncludeDeprecated bool `json:"include_deprecated"`
+	ReadOnly          bool `json:"read_only"`
+	Limit             int  `json:"limit,omitempty"`
+}
+
+// AuditLogger writes audit entries as JSON lines. It is independent of the
+// application log so the audit trail can be shipped to a separate sink.
+type AuditLogger struct {
+	mu     sync.Mutex
+	output io.Writer
+}
+
+// NewAuditLogger creates an audit logger writing to output
+func NewAuditLogger(output io.Writer) *AuditLogger {
+	return &AuditLogger{output: output}
+}
+
+// Log writes entry as a single JSON line, stamping it with the current time
+// when Timestamp is unset
+func (a *AuditLogger) Log(entry AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, err = a.output.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLogger(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf)
+
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := audit.Log(AuditEntry{
+		Timestamp: timestamp,
+		ClientIP:  "10.0.0.1",
+		UserAgent: "agent/1.0",
+		RequestID: "req-123",
+		Tools:     7,
+		Filters: AuditFilters{
+			IncludeProvider: true,
+			ReadOnly:        true,
+			Limit:           5,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	if !strings.HasSuffix(buf.String(), "\n") || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("Expected a single JSON line, got %q", buf.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse audit line: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"timestamp":  "2024-01-02T03:04:05Z",
+		"client_ip":  "10.0.0.1",
+		"user_agent": "agent/1.0",
+		"request_id": "req-123",
+		"tools":      float64(7),
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Errorf("Expected %s %v, got %v", key, want, entry[key])
+		}
+	}
+
+	filters, ok := entry["filters"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected filters object, got %v", entry["filters"])
+	}
+
+	expectedFilters := map[string]interface{}{
+		"include_provider":   true,
+		"include_deprecated": false,
+		"read_only":          true,
+		"limit":              float64(5),
+	}
+	for key, want := range expectedFilters {
+		if filters[key] != want {
+			t.Errorf("Expected filter %s %v, got %v", key, want, filters[key])
+		}
+	}
+}
+
+func TestAuditLoggerDefaultTimestamp(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf)
+
+	before := time.Now().UTC().Add(-time.Second)
+	if err := audit.Log(AuditEntry{ClientIP: "10.0.0.1"}); err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	var entry AuditEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse audit line: %v", err)
+	}
+
+	if entry.Timestamp.Before(before) {
+		t.Errorf("Expected timestamp to be set to now, got %v", entry.Timestamp)
+	}
+}
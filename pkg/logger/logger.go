@@ -7,8 +7,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -73,17 +76,45 @@ type StructuredLogger struct {
 	output     io.Writer
 	fields     map[string]interface{}
 	useColor   bool
+	wantColor  bool
 	showCaller bool
 	timeFormat string
+	utc        bool
+	logfmt     bool
+	redactKeys map[string]bool
+	sampleRate uint64
+	sampled    *atomic.Uint64
+	counts     *levelCounts
 }
 
+// levelCounts tallies written entries per level. Loggers derived with
+// WithField share the same counts.
+type levelCounts [FatalLevel + 1]atomic.Int64
+
 // Config holds logger configuration
 type Config struct {
-	Level      string
-	Output     io.Writer
+	Level  string
+	Output io.Writer
+	// UseColor enables ANSI colors when Output is a terminal. Colors are
+	// never written to files or pipes, or when NO_COLOR is set.
 	UseColor   bool
 	ShowCaller bool
+	// TimeFormat is a time layout such as time.RFC3339Nano; defaults to
+	// "2006-01-02 15:04:05"
 	TimeFormat string
+	// UTC formats timestamps in UTC instead of the local time zone
+	UTC bool
+	// RedactKeys lists field keys whose values are masked in output. Keys
+	// match case-insensitively; keys containing "secret" are always masked.
+	RedactKeys []string
+	// SampleRate logs 1 in N Debug and Info entries; Warn and above are
+	// always logged. Values of 0 or 1 disable sampling.
+	SampleRate int
+	// Logfmt writes strict logfmt for parsers such as Loki: every part of
+	// the entry is a key=value pair, the message is under msg, values with
+	// spaces, '=' or quotes are quoted, and colors are disabled. TimeFormat
+	// defaults to time.RFC3339Nano.
+	Logfmt bool
 }
 
 // New creates a new logger instance
@@ -96,17 +127,38 @@ func New(config Config) *StructuredLogger {
 	}
 
 	timeFormat := config.TimeFormat
-	if timeFormat == "" {
+	if timeFormat == "" && config.Logfmt {
+		timeFormat = time.RFC3339Nano
+	} else if timeFormat == "" {
 		timeFormat = "2006-01-02 15:04:05"
 	}
 
+	wantColor := config.UseColor && !config.Logfmt
+
+	sampleRate := uint64(1)
+	if config.SampleRate > 1 {
+		sampleRate = uint64(config.SampleRate)
+	}
+
+	redactKeys := make(map[string]bool, len(config.RedactKeys))
+	for _, key := range config.RedactKeys {
+		redactKeys[strings.ToLower(key)] = true
+	}
+
 	return &StructuredLogger{
 		level:      level,
 		output:     output,
 		fields:     make(map[string]interface{}),
-		useColor:   config.UseColor,
+		useColor:   colorEnabled(wantColor, output),
+		wantColor:  wantColor,
 		showCaller: config.ShowCaller,
 		timeFormat: timeFormat,
+		utc:        config.UTC,
+		logfmt:     config.Logfmt,
+		redactKeys: redactKeys,
+		sampleRate: sampleRate,
+		sampled:    new(atomic.Uint64),
+		counts:     new(levelCounts),
 	}
 }
 
@@ -118,47 +170,121 @@ func Default() *StructuredLogger {
 	})
 }
 
-// parseLevel converts a string level to LogLevel
+// parseLevel converts a string level to LogLevel, defaulting to InfoLevel
+// for unknown names
 func parseLevel(level string) LogLevel {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return InfoLevel
+	}
+	return parsed
+}
+
+// ParseLevel converts a level name such as "debug" or "warn" to a LogLevel
+func ParseLevel(level string) (LogLevel, error) {
 	switch strings.ToLower(level) {
 	case "debug":
-		return DebugLevel
+		return DebugLevel, nil
 	case "info":
-		return InfoLevel
+		return InfoLevel, nil
 	case "warn", "warning":
-		return WarnLevel
+		return WarnLevel, nil
 	case "error":
-		return ErrorLevel
+		return ErrorLevel, nil
 	case "fatal":
-		return FatalLevel
+		return FatalLevel, nil
 	default:
-		return InfoLevel
+		return InfoLevel, fmt.Errorf("unknown log level %q", level)
 	}
 }
 
-// SetLevel sets the logging level
+// String returns the level's name in lower case, e.g. "debug"
+func (l LogLevel) String() string {
+	return strings.ToLower(levelNames[l])
+}
+
+// SetLevel sets the logging level. Loggers already derived with WithField
+// keep their level.
 func (l *StructuredLogger) SetLevel(level LogLevel) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.level = level
 }
 
+// Level returns the logging level
+func (l *StructuredLogger) Level() LogLevel {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level
+}
+
 // SetOutput sets the output writer
 func (l *StructuredLogger) SetOutput(output io.Writer) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.output = output
+	l.useColor = colorEnabled(l.wantColor, output)
+}
+
+// colorEnabled reports whether colors should be written to output: they must
+// be requested, NO_COLOR must be unset and output must be a terminal
+func colorEnabled(requested bool, output io.Writer) bool {
+	if !requested {
+		return false
+	}
+
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return false
+	}
+
+	return isTerminal(output)
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Counts returns the number of entries written at each level since the
+// logger was created
+func (l *StructuredLogger) Counts() map[LogLevel]int64 {
+	counts := make(map[LogLevel]int64, len(levelNames))
+	for level := range levelNames {
+		counts[level] = l.counts[level].Load()
+	}
+	return counts
+}
+
+// sample reports whether an entry at level should be written. Loggers
+// derived with WithField share a counter so sampling applies across them.
+func (l *StructuredLogger) sample(level LogLevel) bool {
+	if level >= WarnLevel || l.sampleRate <= 1 {
+		return true
+	}
+	return (l.sampled.Add(1)-1)%l.sampleRate == 0
 }
 
 // log is the internal logging method
 func (l *StructuredLogger) log(level LogLevel, args ...interface{}) {
-	if level < l.level {
+	if level < l.Level() || !l.sample(level) {
 		return
 	}
 
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
+	l.counts[level].Add(1)
+
 	// Build the log entry
 	entry := l.formatEntry(level, fmt.Sprint(args...))
 
@@ -173,13 +299,15 @@ func (l *StructuredLogger) log(level LogLevel, args ...interface{}) {
 
 // logf is the internal formatted logging method
 func (l *StructuredLogger) logf(level LogLevel, format string, args ...interface{}) {
-	if level < l.level {
+	if level < l.Level() || !l.sample(level) {
 		return
 	}
 
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
+	l.counts[level].Add(1)
+
 	// Build the log entry
 	entry := l.formatEntry(level, fmt.Sprintf(format, args...))
 
@@ -197,7 +325,15 @@ func (l *StructuredLogger) formatEntry(level LogLevel, message string) string {
 	var parts []string
 
 	// Timestamp
-	parts = append(parts, time.Now().Format(l.timeFormat))
+	now := time.Now()
+	if l.utc {
+		now = now.UTC()
+	}
+
+	if l.logfmt {
+		return l.formatLogfmt(now, level, message)
+	}
+	parts = append(parts, now.Format(l.timeFormat))
 
 	// Level
 	levelStr := levelNames[level]
@@ -230,6 +366,73 @@ func (l *StructuredLogger) formatEntry(level LogLevel, message string) string {
 	return strings.Join(parts, " ") + "\n"
 }
 
+// formatLogfmt formats a log entry as strict logfmt with time, level,
+// caller and msg first, followed by fields in key order
+func (l *StructuredLogger) formatLogfmt(now time.Time, level LogLevel, message string) string {
+	var b strings.Builder
+
+	writeLogfmtPair(&b, "time", now.Format(l.timeFormat))
+	writeLogfmtPair(&b, "level", level.String())
+
+	if l.showCaller {
+		_, file, line, ok := runtime.Caller(5)
+		if ok {
+			writeLogfmtPair(&b, "caller", fmt.Sprintf("%s:%d", filepath.Base(file), line))
+		}
+	}
+
+	writeLogfmtPair(&b, "msg", message)
+
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeLogfmtPair(&b, k, fmt.Sprint(l.fields[k]))
+	}
+
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// writeLogfmtPair appends key=value, separated from any previous pair by a
+// space. Characters that logfmt does not allow in keys are replaced with
+// '_', and values are quoted when needed.
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, key))
+	b.WriteByte('=')
+
+	if logfmtNeedsQuote(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// logfmtNeedsQuote reports whether a logfmt value must be quoted: when it is
+// empty or contains spaces, '=', quotes or control characters
+func logfmtNeedsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
 // Debug logs a debug message
 func (l *StructuredLogger) Debug(args ...interface{}) {
 	l.log(DebugLevel, args...)
@@ -280,6 +483,19 @@ func (l *StructuredLogger) Fatalf(format string, args ...interface{}) {
 	l.logf(FatalLevel, format, args...)
 }
 
+// redactedValue replaces the values of redacted fields
+const redactedValue = "****"
+
+// redact returns the value to log for a field, masking it when the key is
+// configured for redaction or contains "secret"
+func (l *StructuredLogger) redact(key string, value interface{}) interface{} {
+	lower := strings.ToLower(key)
+	if l.redactKeys[lower] || strings.Contains(lower, "secret") {
+		return redactedValue
+	}
+	return value
+}
+
 // WithField creates a new logger with an additional field
 func (l *StructuredLogger) WithField(key string, value interface{}) Logger {
 	l.mu.RLock()
@@ -290,15 +506,22 @@ func (l *StructuredLogger) WithField(key string, value interface{}) Logger {
 	for k, v := range l.fields {
 		newFields[k] = v
 	}
-	newFields[key] = value
+	newFields[key] = l.redact(key, value)
 
 	return &StructuredLogger{
 		level:      l.level,
 		output:     l.output,
 		fields:     newFields,
 		useColor:   l.useColor,
+		wantColor:  l.wantColor,
 		showCaller: l.showCaller,
 		timeFormat: l.timeFormat,
+		utc:        l.utc,
+		logfmt:     l.logfmt,
+		redactKeys: l.redactKeys,
+		sampleRate: l.sampleRate,
+		sampled:    l.sampled,
+		counts:     l.counts,
 	}
 }
 
@@ -313,7 +536,7 @@ func (l *StructuredLogger) WithFields(fields map[string]interface{}) Logger {
 		newFields[k] = v
 	}
 	for k, v := range fields {
-		newFields[k] = v
+		newFields[k] = l.redact(k, v)
 	}
 
 	return &StructuredLogger{
@@ -321,8 +544,15 @@ func (l *StructuredLogger) WithFields(fields map[string]interface{}) Logger {
 		output:     l.output,
 		fields:     newFields,
 		useColor:   l.useColor,
+		wantColor:  l.wantColor,
 		showCaller: l.showCaller,
 		timeFormat: l.timeFormat,
+		utc:        l.utc,
+		logfmt:     l.logfmt,
+		redactKeys: l.redactKeys,
+		sampleRate: l.sampleRate,
+		sampled:    l.sampled,
+		counts:     l.counts,
 	}
 }
 
@@ -2,8 +2,10 @@ package logger
 
 import (
 	"bytes"
+	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -56,6 +58,23 @@ func TestParseLevel(t *testing.T) {
 	}
 }
 
+func TestParseLevelStrict(t *testing.T) {
+	level, err := ParseLevel("Warning")
+	if err != nil || level != WarnLevel {
+		t.Errorf("Expected WarnLevel, got %v (%v)", level, err)
+	}
+
+	for _, input := range []string{"invalid", ""} {
+		if _, err := ParseLevel(input); err == nil {
+			t.Errorf("Expected error for level %q, got nil", input)
+		}
+	}
+
+	if DebugLevel.String() != "debug" {
+		t.Errorf("Expected 'debug', got '%s'", DebugLevel.String())
+	}
+}
+
 func TestLogLevels(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(Config{
@@ -124,6 +143,66 @@ func TestLogLevelFiltering(t *testing.T) {
 	}
 }
 
+func TestSampleRate(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:      "info",
+		Output:     &buf,
+		UseColor:   false,
+		SampleRate: 10,
+	})
+
+	for i := 0; i < 100; i++ {
+		// Derived loggers share the sampling counter
+		logger.WithField("request", i).Info("request completed")
+	}
+
+	infoLines := strings.Count(buf.String(), "request completed")
+	if infoLines != 10 {
+		t.Errorf("Expected 10 sampled info lines, got %d", infoLines)
+	}
+
+	buf.Reset()
+	for i := 0; i < 20; i++ {
+		logger.Errorf("failure %d", i)
+		logger.Warn("warning")
+	}
+
+	if errorLines := strings.Count(buf.String(), "failure"); errorLines != 20 {
+		t.Errorf("Expected all 20 error lines, got %d", errorLines)
+	}
+
+	if warnLines := strings.Count(buf.String(), "warning"); warnLines != 20 {
+		t.Errorf("Expected all 20 warn lines, got %d", warnLines)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{Level: "info", Output: &buf})
+
+	logger.Debug("filtered")
+	logger.Info("info")
+	logger.Warn("warn one")
+	logger.WithField("key", "value").Warnf("warn %d", 2)
+	logger.Error("error")
+
+	counts := logger.Counts()
+
+	expected := map[LogLevel]int64{
+		DebugLevel: 0,
+		InfoLevel:  1,
+		WarnLevel:  2,
+		ErrorLevel: 1,
+		FatalLevel: 0,
+	}
+	for level, want := range expected {
+		if counts[level] != want {
+			t.Errorf("Expected %d %s entries, got %d", want, levelNames[level], counts[level])
+		}
+	}
+}
+
 func TestFormattedLogging(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(Config{
@@ -216,6 +295,68 @@ func TestWithError(t *testing.T) {
 	}
 }
 
+func TestLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:      "info",
+		Output:     &buf,
+		UseColor:   true,
+		UTC:        true,
+		Logfmt:     true,
+		RedactKeys: []string{"token"},
+	})
+
+	logger.WithFields(map[string]interface{}{
+		"path":   "/utcp",
+		"reason": "upstream timed out after 10s",
+		"query":  "a=b",
+		"quote":  `say "hi"`,
+		"empty":  "",
+		"token":  "abc",
+	}).Warn("Failed to refresh tools")
+
+	output := strings.TrimSuffix(buf.String(), "\n")
+
+	for _, want := range []string{
+		`level=warn msg="Failed to refresh tools"`,
+		`reason="upstream timed out after 10s"`,
+		`query="a=b"`,
+		`quote="say \"hi\""`,
+		`empty=""`,
+		`path=/utcp`,
+		`token=****`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in logfmt output, got %s", want, output)
+		}
+	}
+
+	if !strings.HasPrefix(output, "time=") || strings.Contains(output, "\033[") {
+		t.Errorf("Expected uncolored output starting with time=, got %s", output)
+	}
+
+	timestamp := strings.TrimPrefix(strings.Fields(output)[0], "time=")
+	if _, err := time.Parse(time.RFC3339Nano, timestamp); err != nil {
+		t.Errorf("Expected RFC3339 timestamp by default, got %s", timestamp)
+	}
+
+	// Fields are written in key order so entries are stable
+	if strings.Index(output, "empty=") > strings.Index(output, "path=") || strings.Index(output, "path=") > strings.Index(output, "token=") {
+		t.Errorf("Expected fields in key order, got %s", output)
+	}
+}
+
+func TestLogfmtMultilineMessage(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{Level: "info", Output: &buf, Logfmt: true})
+
+	logger.Info("first line\nsecond line")
+
+	if strings.Count(buf.String(), "\n") != 1 || !strings.Contains(buf.String(), `msg="first line\nsecond line"`) {
+		t.Errorf("Expected escaped single-line entry, got %q", buf.String())
+	}
+}
+
 func TestTimeFormat(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(Config{
@@ -241,6 +382,55 @@ func TestTimeFormat(t *testing.T) {
 	}
 }
 
+func TestUTCTimestamps(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:      "info",
+		Output:     &buf,
+		TimeFormat: time.RFC3339Nano,
+		UTC:        true,
+	})
+
+	logger.WithField("key", "value").Info("test")
+
+	timePart := strings.SplitN(buf.String(), " ", 2)[0]
+	if !strings.HasSuffix(timePart, "Z") {
+		t.Errorf("Expected Z-suffixed UTC timestamp, got %s", timePart)
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, timePart); err != nil {
+		t.Errorf("Expected RFC3339 timestamp, got %s: %v", timePart, err)
+	}
+}
+
+func TestRedactKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:      "info",
+		Output:     &buf,
+		RedactKeys: []string{"password", "token", "api_key"},
+	})
+
+	logger.WithField("API_Key", "abc123").WithFields(map[string]interface{}{
+		"client_secret": "shh",
+		"Password":      "hunter2",
+		"user":          "jdoe",
+	}).Info("Configured provider")
+
+	output := buf.String()
+	for _, secret := range []string{"abc123", "shh", "hunter2"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %q to be masked, got %s", secret, output)
+		}
+	}
+
+	for _, want := range []string{"API_Key=****", "client_secret=****", "Password=****", "user=jdoe", "Configured provider"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got %s", want, output)
+		}
+	}
+}
+
 func TestSetLevel(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(Config{
@@ -340,6 +530,8 @@ func TestColors(t *testing.T) {
 		Output:   &buf,
 		UseColor: true,
 	})
+	// Simulate a terminal, which a buffer is not
+	logger.useColor = true
 
 	logger.Debug("debug")
 	logger.Info("info")
@@ -366,6 +558,34 @@ func TestColors(t *testing.T) {
 	}
 }
 
+func TestNoColorForNonTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:    "debug",
+		Output:   &buf,
+		UseColor: true,
+	})
+
+	logger.Info("info")
+	logger.WithField("key", "value").Error("error")
+
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no escape codes for non-terminal output, got %q", buf.String())
+	}
+}
+
+func TestNoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	if colorEnabled(true, os.Stdout) {
+		t.Error("Expected NO_COLOR to disable colors")
+	}
+
+	if colorEnabled(false, os.Stdout) {
+		t.Error("Expected colors to stay disabled when not requested")
+	}
+}
+
 func TestFieldsImmutability(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(Config{
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler adapts a StructuredLogger to the slog.Handler interface
+type slogHandler struct {
+	logger *StructuredLogger
+	group  string
+}
+
+// NewSlogHandler returns an slog.Handler that writes through l. slog levels
+// map to the nearest LogLevel and attributes are logged as fields, with
+// group names joined to keys by dots.
+func NewSlogHandler(l *StructuredLogger) slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// fromSlogLevel converts an slog level to a LogLevel. Levels above error map
+// to ErrorLevel so slog records never exit the process.
+func fromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// Enabled reports whether the logger writes entries at level
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	h.logger.mu.RLock()
+	defer h.logger.mu.RUnlock()
+	return fromSlogLevel(level) >= h.logger.level
+}
+
+// Handle writes the record with its attributes as fields
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	logger := h.logger
+	if record.NumAttrs() > 0 {
+		fields := make(map[string]interface{}, record.NumAttrs())
+		record.Attrs(func(attr slog.Attr) bool {
+			addAttr(fields, h.group, attr)
+			return true
+		})
+		logger = logger.WithFields(fields).(*StructuredLogger)
+	}
+
+	logger.log(fromSlogLevel(record.Level), record.Message)
+	return nil
+}
+
+// WithAttrs returns a handler whose entries include attrs
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		addAttr(fields, h.group, attr)
+	}
+
+	return &slogHandler{
+		logger: h.logger.WithFields(fields).(*StructuredLogger),
+		group:  h.group,
+	}
+}
+
+// WithGroup returns a handler that qualifies later attribute keys with name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &slogHandler{
+		logger: h.logger,
+		group:  joinKey(h.group, name),
+	}
+}
+
+// addAttr flattens attr into fields, prefixing keys with group
+func addAttr(fields map[string]interface{}, group string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		prefix := joinKey(group, attr.Key)
+		for _, member := range value.Group() {
+			addAttr(fields, prefix, member)
+		}
+		return
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	fields[joinKey(group, attr.Key)] = value.Any()
+}
+
+// joinKey joins a group prefix and key with a dot
+func joinKey(group, key string) string {
+	if group == "" {
+		return key
+	}
+	if key == "" {
+		return group
+	}
+	return group + "." + key
+}
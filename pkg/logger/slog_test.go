@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:  "debug",
+		Output: &buf,
+	})
+
+	slogger := slog.New(NewSlogHandler(logger)).With("service", "rh-utcp")
+	slogger.Info("Serving discovery", "tools", 42, slog.Group("request", "ip", "10.0.0.1"))
+
+	output := buf.String()
+	expected := []string{"[INFO]", "Serving discovery", "service=rh-utcp", "tools=42", "request.ip=10.0.0.1"}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got %s", want, output)
+		}
+	}
+}
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected LogLevel
+	}{
+		{slog.LevelDebug, DebugLevel},
+		{slog.LevelInfo, InfoLevel},
+		{slog.LevelWarn, WarnLevel},
+		{slog.LevelError, ErrorLevel},
+		{slog.LevelError + 4, ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		if got := fromSlogLevel(tt.level); got != tt.expected {
+			t.Errorf("Expected %v for slog level %v, got %v", tt.expected, tt.level, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:  "warn",
+		Output: &buf,
+	})
+
+	slogger := slog.New(NewSlogHandler(logger))
+	slogger.Info("filtered")
+	slogger.Warn("written")
+
+	output := buf.String()
+	if strings.Contains(output, "filtered") {
+		t.Error("Expected info entry to be filtered at warn level")
+	}
+	if !strings.Contains(output, "[WARN]") || !strings.Contains(output, "written") {
+		t.Errorf("Expected warn entry to be written, got %s", output)
+	}
+}
+
+func TestSlogHandlerWithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		Level:  "info",
+		Output: &buf,
+	})
+
+	slogger := slog.New(NewSlogHandler(logger)).WithGroup("provider").With("name", "jira")
+	slogger.Info("Refreshed tools", "count", 13)
+
+	output := buf.String()
+	for _, want := range []string{"provider.name=jira", "provider.count=13"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got %s", want, output)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+// Version is the OpenAPI specification version produced by FromManual
+const Version = "3.0.3"
+
+// placeholderPattern matches UTCP ${name} URL placeholders
+var placeholderPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// pathParamPattern matches OpenAPI {name} path parameters
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// OpenAPI is a minimal OpenAPI 3.0 document
+type OpenAPI struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info holds document metadata
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps lower-case HTTP methods to operations
+type PathItem map[string]*Operation
+
+// Server is a base URL an operation is served from
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Operation describes a single API operation
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Servers     []Server            `json:"servers,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes a JSON request body
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes an operation response
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of the OpenAPI schema object derived from UTCP schemas
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+}
+
+// SkippedTool is a tool FromManual could not convert into an operation
+type SkippedTool struct {
+	Name   string
+	Reason string
+}
+
+// String returns the tool name and the reason it was skipped
+func (s SkippedTool) String() string {
+	return fmt.Sprintf("%s: %s", s.Name, s.Reason)
+}
+
+// FromManual converts a UTCP manual into an OpenAPI 3.0 document. Each tool
+// becomes an operation on its tool_provider URL: ${...} placeholders become
+// path parameters, fixed query string values become required query
+// parameters, and the remaining inputs become query parameters for
+// GET/DELETE or a JSON request body otherwise.
+//
+// OpenAPI allows one operation per path and method, so when a tool's path
+// and method are already taken, leading path segments move into the
+// operation's server URL until the path is free; server plus path is still
+// the tool's URL. Tools without an absolute URL, or whose operation cannot
+// be made unique, are returned in skipped rather than failing the document.
+func FromManual(m *utcp.Manual) (*OpenAPI, []SkippedTool) {
+	doc := &OpenAPI{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "RH-UTCP Tools",
+			Description: "Generated from the UTCP manual",
+			Version:     m.Version,
+		},
+		Paths: make(map[string]PathItem),
+	}
+
+	var skipped []SkippedTool
+	for _, tool := range m.Tools {
+		rawURL, _ := tool.ToolProvider["url"].(string)
+		if rawURL == "" {
+			skipped = append(skipped, SkippedTool{Name: tool.Name, Reason: "no tool_provider url"})
+			continue
+		}
+
+		method, _ := tool.ToolProvider["http_method"].(string)
+		if method == "" {
+			method = "GET"
+		}
+		method = strings.ToLower(method)
+
+		server, path, query, err := splitURL(rawURL)
+		if err != nil {
+			skipped = append(skipped, SkippedTool{Name: tool.Name, Reason: err.Error()})
+			continue
+		}
+
+		server, path, ok := uniquePath(doc, server, path, method)
+		if !ok {
+			skipped = append(skipped, SkippedTool{
+				Name:   tool.Name,
+				Reason: fmt.Sprintf("%s %s cannot be told apart from an earlier tool", strings.ToUpper(method), rawURL),
+			})
+			continue
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][method] = toOperation(tool, server, path, method, query)
+	}
+
+	return doc, skipped
+}
+
+// uniquePath returns a server and path for an operation that no earlier
+// operation uses with the same method. While the path is taken, its leading
+// segment moves to the end of the server URL; segments holding a parameter
+// stay in the path, since the server URL cannot hold one.
+func uniquePath(doc *OpenAPI, server, path, method string) (string, string, bool) {
+	for doc.Paths[path][method] != nil {
+		segment, rest, found := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+		if !found || rest == "" || strings.Contains(segment, "{") {
+			return "", "", false
+		}
+		server += "/" + segment
+		path = "/" + rest
+	}
+	return server, path, true
+}
+
+// queryParam is a fixed name=value pair from a tool URL's query string
+type queryParam struct {
+	Name  string
+	Value string
+}
+
+// splitURL separates a tool URL into its server, OpenAPI path template and
+// fixed query parameters. APIs such as TestRail route calls through the
+// query string (index.php?/api/v2/...); for those the script and "?" stay in
+// the server URL and the route in the query becomes the path, so each call
+// keeps its own path.
+func splitURL(rawURL string) (string, string, []queryParam, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid url %s: %w", rawURL, err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", "", nil, fmt.Errorf("url %s must be absolute", rawURL)
+	}
+
+	server := u.Scheme + "://" + u.Host
+	path := u.Path
+	rawQuery := u.RawQuery
+	if strings.HasPrefix(rawQuery, "/") {
+		server += u.Path + "?"
+		path, rawQuery, _ = strings.Cut(rawQuery, "&")
+	}
+	if path == "" {
+		path = "/"
+	}
+	path = placeholderPattern.ReplaceAllString(path, "{$1}")
+
+	var query []queryParam
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		query = append(query, queryParam{Name: name, Value: value})
+	}
+
+	return server, path, query, nil
+}
+
+// toOperation builds the operation for a tool
+func toOperation(tool utcp.Tool, server, path, method string, query []queryParam) *Operation {
+	operation := &Operation{
+		OperationID: tool.Name,
+		Summary:     tool.Description,
+		Tags:        tool.Tags,
+		Servers:     []Server{{URL: server}},
+		Responses: map[string]Response{
+			"200": {
+				Description: responseDescription(tool.Outputs),
+				Content: map[string]MediaType{
+					"application/json": {Schema: fromSchema(tool.Outputs)},
+				},
+			},
+		},
+	}
+
+	required := make(map[string]bool)
+	for _, name := range tool.Inputs.Required {
+		required[name] = true
+	}
+
+	pathParams := make(map[string]bool)
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		pathParams[match[1]] = true
+	}
+
+	// Fixed query values are sent as-is; inputs filling a ${...} in one are
+	// part of that value rather than parameters of their own
+	fixed := make(map[string]bool)
+	for _, param := range query {
+		fixed[param.Name] = true
+
+		schema := &Schema{Type: "string"}
+		description := "Fixed value"
+		if placeholderPattern.MatchString(param.Value) {
+			description = fmt.Sprintf("Fixed value %s with ${...} filled in from the tool inputs", param.Value)
+			for _, match := range placeholderPattern.FindAllStringSubmatch(param.Value, -1) {
+				fixed[match[1]] = true
+			}
+		} else {
+			schema.Enum = []string{param.Value}
+			schema.Default = param.Value
+		}
+
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: description,
+			Required:    true,
+			Schema:      schema,
+		})
+	}
+
+	// Sort property names so the output is deterministic
+	names := make([]string, 0, len(tool.Inputs.Properties))
+	for name := range tool.Inputs.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	useBody := method != "get" && method != "delete"
+	body := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	// A body_field input is the entire request body rather than a property
+	bodyField, _ := tool.ToolProvider["body_field"].(string)
+
+	for _, name := range names {
+		property := tool.Inputs.Properties[name]
+
+		switch {
+		case fixed[name] && !pathParams[name]:
+			continue
+		case pathParams[name]:
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:        name,
+				In:          "path",
+				Description: property.Description,
+				Required:    true,
+				Schema:      fromProperty(property),
+			})
+		case useBody && name == bodyField:
+			operation.RequestBody = &RequestBody{
+				Required: required[name],
+				Content: map[string]MediaType{
+					"application/json": {Schema: fromProperty(property)},
+				},
+			}
+		case useBody:
+			body.Properties[name] = fromProperty(property)
+			if required[name] {
+				body.Required = append(body.Required, name)
+			}
+		default:
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:        name,
+				In:          "query",
+				Description: property.Description,
+				Required:    required[name],
+				Schema:      fromProperty(property),
+			})
+		}
+	}
+
+	// Path placeholders without a declared input still need a parameter
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		if _, declared := tool.Inputs.Properties[name]; !declared {
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	if useBody && operation.RequestBody == nil && len(body.Properties) > 0 {
+		operation.RequestBody = &RequestBody{
+			Required: len(body.Required) > 0,
+			Content: map[string]MediaType{
+				"application/json": {Schema: body},
+			},
+		}
+	}
+
+	return operation
+}
+
+// fromProperty converts a UTCP property to an OpenAPI schema
+func fromProperty(property utcp.Property) *Schema {
+	schema := &Schema{
+		Type:        property.Type,
+		Description: property.Description,
+		Enum:        property.Enum,
+		Default:     property.Default,
+	}
+
+	// OpenAPI requires items for arrays
+	if schema.Type == "array" {
+		schema.Items = &Schema{}
+	}
+
+	return schema
+}
+
+// fromSchema converts a UTCP schema to an OpenAPI schema
+func fromSchema(s utcp.Schema) *Schema {
+	schema := &Schema{
+		Type:        s.Type,
+		Description: s.Description,
+		Required:    s.Required,
+	}
+
+	if len(s.Properties) > 0 {
+		schema.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			schema.Properties[name] = fromProperty(property)
+		}
+	}
+
+	if schema.Type == "array" {
+		schema.Items = &Schema{}
+	}
+
+	return schema
+}
+
+// responseDescription returns a non-empty response description
+func responseDescription(s utcp.Schema) string {
+	if s.Description != "" {
+		return s.Description
+	}
+	return "Successful response"
+}
@@ -0,0 +1,411 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rh-utcp/rh-utcp/pkg/utcp"
+)
+
+func testManual() *utcp.Manual {
+	manual := utcp.NewManual()
+
+	manual.AddTool(utcp.Tool{
+		Name:        "get_issue",
+		Description: "Get an issue",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {Type: "string", Description: "Issue key"},
+				"fields":   {Type: "array", Description: "Fields to return"},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{Type: "object", Description: "Issue details"},
+		Tags:    []string{"issues"},
+		ToolProvider: utcp.HTTPProvider(
+			"get_issue",
+			"https://jira.example.com/rest/api/2/issue/${issueKey}",
+			"GET",
+			nil,
+		),
+	})
+
+	manual.AddTool(utcp.Tool{
+		Name:        "create_issue",
+		Description: "Create an issue",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"summary":  {Type: "string", Description: "Summary"},
+				"priority": {Type: "string", Enum: []string{"low", "high"}},
+			},
+			Required: []string{"summary"},
+		},
+		Outputs: utcp.Schema{Type: "object"},
+		ToolProvider: utcp.HTTPProvider(
+			"create_issue",
+			"https://jira.example.com/rest/api/2/issue",
+			"POST",
+			nil,
+		),
+	})
+
+	manual.AddTool(utcp.Tool{
+		Name:    "search_issues",
+		Inputs:  utcp.Schema{Type: "object"},
+		Outputs: utcp.Schema{Type: "array"},
+		ToolProvider: utcp.HTTPProvider(
+			"search_issues",
+			"https://jira.example.com/rest/api/2/search",
+			"GET",
+			nil,
+		),
+	})
+
+	return manual
+}
+
+func TestFromManual(t *testing.T) {
+	doc, skipped := FromManual(testManual())
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	if doc.OpenAPI != Version {
+		t.Errorf("Expected openapi %s, got %s", Version, doc.OpenAPI)
+	}
+
+	if doc.Info.Version != "0.1.0" {
+		t.Errorf("Expected info version 0.1.0, got %s", doc.Info.Version)
+	}
+
+	item, exists := doc.Paths["/rest/api/2/issue/{issueKey}"]
+	if !exists {
+		t.Fatal("Expected path /rest/api/2/issue/{issueKey}")
+	}
+
+	get := item["get"]
+	if get == nil {
+		t.Fatal("Expected get operation")
+	}
+
+	if get.OperationID != "get_issue" {
+		t.Errorf("Expected operationId 'get_issue', got %s", get.OperationID)
+	}
+
+	if len(get.Servers) != 1 || get.Servers[0].URL != "https://jira.example.com" {
+		t.Errorf("Expected server https://jira.example.com, got %v", get.Servers)
+	}
+
+	params := make(map[string]Parameter)
+	for _, p := range get.Parameters {
+		params[p.Name] = p
+	}
+
+	if p := params["issueKey"]; p.In != "path" || !p.Required {
+		t.Errorf("Expected issueKey to be a required path parameter, got %+v", p)
+	}
+
+	if p := params["fields"]; p.In != "query" || p.Schema.Items == nil {
+		t.Errorf("Expected fields to be a query array parameter with items, got %+v", p)
+	}
+
+	if get.Responses["200"].Content["application/json"].Schema.Type != "object" {
+		t.Error("Expected 200 response schema of type object")
+	}
+}
+
+func TestFromManualRequestBody(t *testing.T) {
+	doc, skipped := FromManual(testManual())
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	post := doc.Paths["/rest/api/2/issue"]["post"]
+	if post == nil {
+		t.Fatal("Expected post operation on /rest/api/2/issue")
+	}
+
+	if len(post.Parameters) != 0 {
+		t.Errorf("Expected no parameters for POST, got %d", len(post.Parameters))
+	}
+
+	if post.RequestBody == nil || !post.RequestBody.Required {
+		t.Fatal("Expected a required request body")
+	}
+
+	schema := post.RequestBody.Content["application/json"].Schema
+	if len(schema.Properties) != 2 {
+		t.Errorf("Expected 2 body properties, got %d", len(schema.Properties))
+	}
+
+	if len(schema.Required) != 1 || schema.Required[0] != "summary" {
+		t.Errorf("Expected 'summary' to be required, got %v", schema.Required)
+	}
+
+	// Responses without a description still need one to be valid
+	if post.Responses["200"].Description == "" {
+		t.Error("Expected a non-empty response description")
+	}
+}
+
+func TestFromManualBodyField(t *testing.T) {
+	manual := utcp.NewManual()
+	manual.AddTool(utcp.Tool{
+		Name: "add_watcher",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"issueKey": {Type: "string"},
+				"username": {Type: "string", Description: "Username"},
+			},
+			Required: []string{"issueKey"},
+		},
+		Outputs: utcp.Schema{Type: "object"},
+		ToolProvider: utcp.HTTPProvider(
+			"add_watcher",
+			"https://jira.example.com/rest/api/2/issue/${issueKey}/watchers",
+			"POST",
+			nil,
+			utcp.WithBodyField("username"),
+		),
+	})
+
+	doc, skipped := FromManual(manual)
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	post := doc.Paths["/rest/api/2/issue/{issueKey}/watchers"]["post"]
+	if post == nil || post.RequestBody == nil {
+		t.Fatal("Expected a request body")
+	}
+
+	if post.RequestBody.Required {
+		t.Error("Expected optional request body for an optional body_field")
+	}
+
+	schema := post.RequestBody.Content["application/json"].Schema
+	if schema.Type != "string" {
+		t.Errorf("Expected a bare string body, got %s", schema.Type)
+	}
+}
+
+func TestFromManualSharedEndpoint(t *testing.T) {
+	manual := testManual()
+	for _, name := range []string{"user_issues", "filter_issues"} {
+		manual.AddTool(utcp.Tool{
+			Name:    name,
+			Inputs:  utcp.Schema{Type: "object"},
+			Outputs: utcp.Schema{Type: "array"},
+			ToolProvider: utcp.HTTPProvider(
+				name,
+				"https://jira.example.com/rest/api/2/search",
+				"GET",
+				nil,
+			),
+		})
+	}
+
+	doc, skipped := FromManual(manual)
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	expected := map[string]struct{ server, path string }{
+		"search_issues": {"https://jira.example.com", "/rest/api/2/search"},
+		"user_issues":   {"https://jira.example.com/rest", "/api/2/search"},
+		"filter_issues": {"https://jira.example.com/rest/api", "/2/search"},
+	}
+
+	for name, want := range expected {
+		operation := doc.Paths[want.path]["get"]
+		if operation == nil || operation.OperationID != name {
+			t.Errorf("Expected %s at GET %s, got %+v", name, want.path, operation)
+			continue
+		}
+		if operation.Servers[0].URL != want.server {
+			t.Errorf("Expected %s server %s, got %s", name, want.server, operation.Servers[0].URL)
+		}
+	}
+}
+
+func TestFromManualSharedEndpointExhausted(t *testing.T) {
+	manual := utcp.NewManual()
+	for _, name := range []string{"first", "second"} {
+		manual.AddTool(utcp.Tool{
+			Name:         name,
+			ToolProvider: utcp.HTTPProvider(name, "https://api.example.com/${id}", "GET", nil),
+		})
+	}
+
+	doc, skipped := FromManual(manual)
+	if len(skipped) != 1 || skipped[0].Name != "second" {
+		t.Fatalf("Expected second to be skipped, got %v", skipped)
+	}
+
+	if doc.Paths["/{id}"]["get"].OperationID != "first" {
+		t.Errorf("Expected first to keep the endpoint, got %+v", doc.Paths)
+	}
+}
+
+func TestFromManualFixedQuery(t *testing.T) {
+	manual := utcp.NewManual()
+	manual.AddTool(utcp.Tool{
+		Name: "get_attachments",
+		Inputs: utcp.Schema{
+			Type:       "object",
+			Properties: map[string]utcp.Property{"issueKey": {Type: "string"}},
+		},
+		ToolProvider: utcp.HTTPProvider(
+			"get_attachments",
+			"https://jira.example.com/rest/api/2/issue/${issueKey}?fields=attachment",
+			"GET",
+			nil,
+		),
+	})
+	manual.AddTool(utcp.Tool{
+		Name: "run_filter",
+		Inputs: utcp.Schema{
+			Type: "object",
+			Properties: map[string]utcp.Property{
+				"filterId":   {Type: "string"},
+				"maxResults": {Type: "integer"},
+			},
+		},
+		ToolProvider: utcp.HTTPProvider(
+			"run_filter",
+			"https://jira.example.com/rest/api/2/search?jql=filter=${filterId}",
+			"GET",
+			nil,
+		),
+	})
+
+	doc, skipped := FromManual(manual)
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	attachments := doc.Paths["/rest/api/2/issue/{issueKey}"]["get"]
+	if attachments == nil {
+		t.Fatalf("Expected get_attachments operation, got %v", doc.Paths)
+	}
+
+	var fields *Parameter
+	for i, param := range attachments.Parameters {
+		if param.Name == "fields" {
+			fields = &attachments.Parameters[i]
+		}
+	}
+	if fields == nil || fields.In != "query" || !fields.Required {
+		t.Fatalf("Expected required fields query parameter, got %+v", attachments.Parameters)
+	}
+	if len(fields.Schema.Enum) != 1 || fields.Schema.Enum[0] != "attachment" {
+		t.Errorf("Expected fields fixed to attachment, got %+v", fields.Schema)
+	}
+
+	filter := doc.Paths["/rest/api/2/search"]["get"]
+	if filter == nil {
+		t.Fatalf("Expected run_filter operation, got %v", doc.Paths)
+	}
+
+	names := make([]string, 0, len(filter.Parameters))
+	for _, param := range filter.Parameters {
+		names = append(names, param.Name)
+	}
+	if strings.Join(names, ",") != "jql,maxResults" {
+		t.Errorf("Expected jql and maxResults parameters, got %v", names)
+	}
+	if !strings.Contains(filter.Parameters[0].Description, "filter=${filterId}") {
+		t.Errorf("Expected jql description to show the template, got %q", filter.Parameters[0].Description)
+	}
+}
+
+func TestFromManualQueryRoutedPaths(t *testing.T) {
+	manual := utcp.NewManual()
+	for _, name := range []string{"get_runs", "get_case"} {
+		manual.AddTool(utcp.Tool{
+			Name:    name,
+			Inputs:  utcp.Schema{Type: "object"},
+			Outputs: utcp.Schema{Type: "object"},
+			ToolProvider: utcp.HTTPProvider(
+				name,
+				"https://testrail.example.com/index.php?/api/v2/"+name+"/${id}",
+				"GET",
+				nil,
+			),
+		})
+	}
+
+	doc, skipped := FromManual(manual)
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	for _, name := range []string{"get_runs", "get_case"} {
+		operation := doc.Paths["/api/v2/"+name+"/{id}"]["get"]
+		if operation == nil {
+			t.Fatalf("Expected path /api/v2/%s/{id}, got %v", name, doc.Paths)
+		}
+
+		if len(operation.Servers) != 1 || operation.Servers[0].URL != "https://testrail.example.com/index.php?" {
+			t.Errorf("Expected server with the routing script, got %v", operation.Servers)
+		}
+
+		if len(operation.Parameters) != 1 || operation.Parameters[0].Name != "id" || operation.Parameters[0].In != "path" {
+			t.Errorf("Expected id path parameter, got %+v", operation.Parameters)
+		}
+	}
+}
+
+func TestFromManualJSON(t *testing.T) {
+	doc, skipped := FromManual(testManual())
+	if len(skipped) > 0 {
+		t.Fatalf("Expected no skipped tools, got %v", skipped)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+
+	for _, key := range []string{"openapi", "info", "paths"} {
+		if _, exists := parsed[key]; !exists {
+			t.Errorf("Missing top-level field '%s'", key)
+		}
+	}
+}
+
+func TestFromManualInvalidURL(t *testing.T) {
+	manual := testManual()
+	manual.AddTool(utcp.Tool{Name: "no_provider"})
+	manual.AddTool(utcp.Tool{
+		Name:         "relative",
+		ToolProvider: utcp.HTTPProvider("relative", "/api/things", "GET", nil),
+	})
+
+	doc, skipped := FromManual(manual)
+
+	names := make([]string, 0, len(skipped))
+	for _, tool := range skipped {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "no_provider,relative" {
+		t.Errorf("Expected no_provider and relative to be skipped, got %v", skipped)
+	}
+
+	operations := 0
+	for _, item := range doc.Paths {
+		operations += len(item)
+	}
+	if operations != 3 {
+		t.Errorf("Expected the other 3 tools in the document, got %d", operations)
+	}
+}
@@ -0,0 +1,78 @@
+package utcp
+
+import (
+	"sort"
+)
+
+// Diff describes how the tool surface changed between two manuals. Each list
+// holds tool names in sorted order.
+type Diff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// Empty reports whether the manuals expose the same tools
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffManuals compares the tools of two manuals by name. A tool counts as
+// changed when its inputs, outputs or tool provider differ; descriptions,
+// tags, examples and response size hints are ignored. A nil manual is
+// treated as having no tools.
+func DiffManuals(old, new *Manual) Diff {
+	oldHashes := toolHashes(old)
+	newHashes := toolHashes(new)
+
+	diff := Diff{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []string{},
+	}
+
+	for name, hash := range newHashes {
+		oldHash, exists := oldHashes[name]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, name)
+		case oldHash != hash:
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+
+	for name := range oldHashes {
+		if _, exists := newHashes[name]; !exists {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	return diff
+}
+
+// toolHashes returns the surface hash of each tool in the manual by name
+func toolHashes(m *Manual) map[string]string {
+	hashes := make(map[string]string)
+	if m == nil {
+		return hashes
+	}
+
+	for _, tool := range m.Tools {
+		hashes[tool.Name] = toolSurfaceHash(tool)
+	}
+
+	return hashes
+}
+
+// toolSurfaceHash hashes the parts of a tool that affect how it is called
+func toolSurfaceHash(tool Tool) string {
+	return Tool{
+		Inputs:       tool.Inputs,
+		Outputs:      tool.Outputs,
+		ToolProvider: tool.ToolProvider,
+	}.Fingerprint()
+}
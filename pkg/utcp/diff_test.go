@@ -0,0 +1,88 @@
+package utcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func diffTool(name, url string) Tool {
+	return Tool{
+		Name:        name,
+		Description: "Tool " + name,
+		Inputs: Schema{
+			Type: "object",
+			Properties: map[string]Property{
+				"id": {Type: "string", Description: "Identifier"},
+			},
+		},
+		Outputs:      Schema{Type: "object"},
+		ToolProvider: HTTPProvider(name, url, "GET", nil),
+	}
+}
+
+func TestDiffManuals(t *testing.T) {
+	old := NewManual()
+	old.AddTool(diffTool("kept", "https://example.com/kept"))
+	old.AddTool(diffTool("removed", "https://example.com/removed"))
+	old.AddTool(diffTool("moved", "https://example.com/old"))
+	old.AddTool(diffTool("reshaped", "https://example.com/reshaped"))
+
+	new := NewManual()
+	new.AddTool(diffTool("added", "https://example.com/added"))
+	new.AddTool(diffTool("moved", "https://example.com/new"))
+
+	kept := diffTool("kept", "https://example.com/kept")
+	kept.Description = "Reworded description"
+	kept.AverageResponseSize = 500
+	new.AddTool(kept)
+
+	reshaped := diffTool("reshaped", "https://example.com/reshaped")
+	reshaped.Inputs.Required = []string{"id"}
+	new.AddTool(reshaped)
+
+	diff := DiffManuals(old, new)
+
+	if got := strings.Join(diff.Added, ","); got != "added" {
+		t.Errorf("Expected added [added], got %v", diff.Added)
+	}
+
+	if got := strings.Join(diff.Removed, ","); got != "removed" {
+		t.Errorf("Expected removed [removed], got %v", diff.Removed)
+	}
+
+	if got := strings.Join(diff.Changed, ","); got != "moved,reshaped" {
+		t.Errorf("Expected changed [moved reshaped], got %v", diff.Changed)
+	}
+
+	if diff.Empty() {
+		t.Error("Expected diff not to be empty")
+	}
+}
+
+func TestDiffManualsIdentical(t *testing.T) {
+	old := NewManual()
+	old.AddTool(diffTool("a", "https://example.com/a"))
+	old.AddTool(diffTool("b", "https://example.com/b"))
+
+	// Same tools in a different order
+	new := NewManual()
+	new.AddTool(diffTool("b", "https://example.com/b"))
+	new.AddTool(diffTool("a", "https://example.com/a"))
+
+	if diff := DiffManuals(old, new); !diff.Empty() {
+		t.Errorf("Expected empty diff, got %+v", diff)
+	}
+}
+
+func TestDiffManualsNil(t *testing.T) {
+	manual := NewManual()
+	manual.AddTool(diffTool("a", "https://example.com/a"))
+
+	if diff := DiffManuals(nil, manual); len(diff.Added) != 1 || diff.Added[0] != "a" {
+		t.Errorf("Expected [a] added from nil manual, got %v", diff.Added)
+	}
+
+	if diff := DiffManuals(manual, nil); len(diff.Removed) != 1 || diff.Removed[0] != "a" {
+		t.Errorf("Expected [a] removed to nil manual, got %v", diff.Removed)
+	}
+}
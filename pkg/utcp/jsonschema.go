@@ -0,0 +1,61 @@
+package utcp
+
+// JSONSchemaDraft is the JSON Schema dialect produced by SchemaToJSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaToJSONSchema converts a tool input schema to a standalone JSON Schema
+// object, for agent frameworks that take plain JSON Schema for function
+// calling rather than full UTCP tools. Properties and required are always
+// present so callers need not check for them.
+func SchemaToJSONSchema(s Schema) map[string]interface{} {
+	schemaType := s.Type
+	if schemaType == "" {
+		schemaType = "object"
+	}
+
+	properties := make(map[string]interface{}, len(s.Properties))
+	for name, prop := range s.Properties {
+		properties[name] = propertyToJSONSchema(prop)
+	}
+
+	required := s.Required
+	if required == nil {
+		required = []string{}
+	}
+
+	result := map[string]interface{}{
+		"$schema":    JSONSchemaDraft,
+		"type":       schemaType,
+		"properties": properties,
+		"required":   required,
+	}
+	if s.Title != "" {
+		result["title"] = s.Title
+	}
+	if s.Description != "" {
+		result["description"] = s.Description
+	}
+	if s.AdditionalProperties != nil {
+		result["additionalProperties"] = *s.AdditionalProperties
+	}
+
+	return result
+}
+
+// propertyToJSONSchema converts a single property, omitting empty fields
+func propertyToJSONSchema(p Property) map[string]interface{} {
+	result := map[string]interface{}{"type": p.Type}
+	if p.Description != "" {
+		result["description"] = p.Description
+	}
+	if len(p.Enum) > 0 {
+		result["enum"] = p.Enum
+	}
+	if p.Default != nil {
+		result["default"] = p.Default
+	}
+	if p.Format != "" {
+		result["format"] = p.Format
+	}
+	return result
+}
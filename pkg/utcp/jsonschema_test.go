@@ -0,0 +1,70 @@
+package utcp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaToJSONSchema(t *testing.T) {
+	schema := SchemaToJSONSchema(Schema{
+		Type: "object",
+		Properties: map[string]Property{
+			"state": {Type: "string", Description: "Issue state", Enum: []string{"opened", "closed"}, Default: "opened"},
+			"since": {Type: "string", Format: "date-time"},
+		},
+		Required:             []string{"state"},
+		AdditionalProperties: Bool(false),
+	})
+
+	if schema["$schema"] != JSONSchemaDraft {
+		t.Errorf("Expected $schema %s, got %v", JSONSchemaDraft, schema["$schema"])
+	}
+
+	if schema["type"] != "object" {
+		t.Errorf("Expected type object, got %v", schema["type"])
+	}
+
+	if !reflect.DeepEqual(schema["required"], []string{"state"}) {
+		t.Errorf("Expected required [state], got %v", schema["required"])
+	}
+
+	if schema["additionalProperties"] != false {
+		t.Errorf("Expected additionalProperties false, got %v", schema["additionalProperties"])
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	state := properties["state"].(map[string]interface{})
+
+	if !reflect.DeepEqual(state["enum"], []string{"opened", "closed"}) {
+		t.Errorf("Expected enum to survive, got %v", state["enum"])
+	}
+
+	if state["default"] != "opened" {
+		t.Errorf("Expected default 'opened', got %v", state["default"])
+	}
+
+	since := properties["since"].(map[string]interface{})
+	if since["format"] != "date-time" {
+		t.Errorf("Expected format date-time, got %v", since["format"])
+	}
+
+	if _, ok := since["description"]; ok {
+		t.Error("Expected empty description to be omitted")
+	}
+}
+
+func TestSchemaToJSONSchemaEmpty(t *testing.T) {
+	schema := SchemaToJSONSchema(Schema{})
+
+	if schema["type"] != "object" {
+		t.Errorf("Expected type to default to object, got %v", schema["type"])
+	}
+
+	if properties := schema["properties"].(map[string]interface{}); len(properties) != 0 {
+		t.Errorf("Expected empty properties, got %v", properties)
+	}
+
+	if required := schema["required"].([]string); required == nil || len(required) != 0 {
+		t.Errorf("Expected empty non-nil required, got %v", required)
+	}
+}
@@ -1,13 +1,40 @@
 package utcp
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Manual represents a UTCP manual with version and tools
 type Manual struct {
-	Version string `json:"version"`
-	Tools   []Tool `json:"tools"`
+	Version      string        `json:"version"`
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+	Tools        []Tool        `json:"tools"`
+}
+
+// Capabilities advertises what the serving instance supports so agents can
+// decide which tools they are able to use
+type Capabilities struct {
+	ProviderTypes []string `json:"provider_types"`
+	AuthTypes     []string `json:"auth_types"`
+	ManualVersion string   `json:"manual_version"`
+}
+
+// AuthTypes returns the auth_type values produced by this package's auth
+// helpers
+func AuthTypes() []string {
+	return []string{"api_key", "basic", "oauth2", "personal_token"}
 }
 
 // Tool represents a single tool in the UTCP manual
@@ -18,24 +45,248 @@ type Tool struct {
 	Outputs             Schema                 `json:"outputs"`
 	Tags                []string               `json:"tags,omitempty"`
 	AverageResponseSize int                    `json:"average_response_size,omitempty"`
-	ToolProvider        map[string]interface{} `json:"tool_provider"`
+	AverageLatencyMs    int                    `json:"average_latency_ms,omitempty"`
+	InputLocation       string                 `json:"input_location,omitempty"`
+	Examples            []ToolExample          `json:"examples,omitempty"`
+	Deprecated          bool                   `json:"deprecated,omitempty"`
+	DeprecationMessage  string                 `json:"deprecation_message,omitempty"`
+	Retry               *RetrySpec             `json:"retry,omitempty"`
+	Sensitive           bool                   `json:"sensitive,omitempty"`
+	Mutating            bool                   `json:"mutating,omitempty"`
+	DependsOn           []string               `json:"depends_on,omitempty"`
+	ToolProvider        map[string]interface{} `json:"tool_provider,omitempty"`
+}
+
+// RetrySpec tells agents how to retry a failed tool call
+type RetrySpec struct {
+	MaxAttempts   int   `json:"max_attempts"`
+	BackoffBaseMs int   `json:"backoff_base_ms"`
+	RetryOn       []int `json:"retry_on,omitempty"`
+}
+
+// DefaultRetrySpec returns the retry policy for idempotent tools: up to three
+// attempts with exponential backoff from 200ms on 502 and 503 responses
+func DefaultRetrySpec() *RetrySpec {
+	return &RetrySpec{
+		MaxAttempts:   3,
+		BackoffBaseMs: 200,
+		RetryOn:       []int{502, 503},
+	}
+}
+
+// WithDefaultRetry sets DefaultRetrySpec on http GET tools that have no retry
+// spec. Other methods are left without one since they may not be idempotent.
+func WithDefaultRetry(tools []Tool) []Tool {
+	for i := range tools {
+		if tools[i].Retry != nil {
+			continue
+		}
+
+		if method, _ := tools[i].ToolProvider["http_method"].(string); method == "GET" {
+			tools[i].Retry = DefaultRetrySpec()
+		}
+	}
+
+	return tools
+}
+
+// Input locations tell clients where to send a tool's inputs
+const (
+	// InputLocationQuery sends inputs as URL query parameters
+	InputLocationQuery = "query"
+	// InputLocationBody sends inputs as a JSON request body
+	InputLocationBody = "body"
+	// InputLocationPath substitutes every input into ${name} URL placeholders
+	InputLocationPath = "path"
+	// InputLocationMixed fills URL placeholders first and sends the remaining
+	// inputs as query parameters or body according to the method
+	InputLocationMixed = "mixed"
+)
+
+// InferInputLocation derives where an http tool's inputs go from its method
+// and URL: GET and DELETE use the query string, POST, PUT and PATCH use the
+// body, and inputs named by ${name} URL placeholders go in the path. Tools
+// without an http method get "".
+func InferInputLocation(tool Tool) string {
+	method, _ := tool.ToolProvider["http_method"].(string)
+	url, _ := tool.ToolProvider["url"].(string)
+
+	var location string
+	switch strings.ToUpper(method) {
+	case "GET", "DELETE":
+		location = InputLocationQuery
+	case "POST", "PUT", "PATCH":
+		location = InputLocationBody
+	default:
+		return ""
+	}
+
+	inPath := 0
+	for name := range tool.Inputs.Properties {
+		if strings.Contains(url, "${"+name+"}") {
+			inPath++
+		}
+	}
+
+	switch {
+	case inPath == 0:
+		return location
+	case inPath == len(tool.Inputs.Properties):
+		return InputLocationPath
+	default:
+		return InputLocationMixed
+	}
 }
 
-// Schema represents input/output schema for a tool
+// WithInputLocations sets InferInputLocation on tools that have no input
+// location
+func WithInputLocations(tools []Tool) []Tool {
+	for i := range tools {
+		if tools[i].InputLocation == "" {
+			tools[i].InputLocation = InferInputLocation(tools[i])
+		}
+	}
+
+	return tools
+}
+
+// ToolExample is a complete sample invocation of a tool
+type ToolExample struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Input       map[string]interface{} `json:"input"`
+	Output      map[string]interface{} `json:"output,omitempty"`
+}
+
+// Schema represents input/output schema for a tool. AdditionalProperties is
+// false for schemas that reject undeclared properties and nil when unspecified.
 type Schema struct {
-	Type        string              `json:"type"`
-	Properties  map[string]Property `json:"properties,omitempty"`
-	Required    []string            `json:"required,omitempty"`
-	Description string              `json:"description,omitempty"`
-	Title       string              `json:"title,omitempty"`
+	Type                 string              `json:"type"`
+	Properties           map[string]Property `json:"properties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	Title                string              `json:"title,omitempty"`
+	AdditionalProperties *bool               `json:"additionalProperties,omitempty"`
 }
 
-// Property represents a single property in a schema
+// Bool returns a pointer to v, for optional schema fields
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Property represents a single property in a schema. Format is an optional
+// JSON Schema format hint such as "date-time", "date", "uri" or "email".
 type Property struct {
 	Type        string      `json:"type"`
 	Description string      `json:"description"`
 	Enum        []string    `json:"enum,omitempty"`
 	Default     interface{} `json:"default,omitempty"`
+	Format      string      `json:"format,omitempty"`
+}
+
+// Validate checks that a string default is one of the property's enum
+// values. Non-string defaults are not checked.
+func (p Property) Validate() error {
+	value, ok := p.Default.(string)
+	if !ok || len(p.Enum) == 0 {
+		return nil
+	}
+
+	for _, option := range p.Enum {
+		if option == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("default %q is not one of %v", value, p.Enum)
+}
+
+// validHTTPMethods lists the HTTP methods accepted for http tool providers
+var validHTTPMethods = map[string]bool{
+	"GET":    true,
+	"POST":   true,
+	"PUT":    true,
+	"PATCH":  true,
+	"DELETE": true,
+}
+
+// ValidHTTPMethod reports whether method is accepted for http tool providers.
+// Methods must be upper case.
+func ValidHTTPMethod(method string) bool {
+	return validHTTPMethods[method]
+}
+
+// Validate checks that the tool has the fields UTCP clients rely on
+func (t Tool) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+
+	if t.Inputs.Type == "" {
+		return fmt.Errorf("tool %s: inputs type is required", t.Name)
+	}
+
+	for name, property := range t.Inputs.Properties {
+		if err := property.Validate(); err != nil {
+			return fmt.Errorf("tool %s: input %s: %w", t.Name, name, err)
+		}
+	}
+
+	for _, name := range t.Inputs.Required {
+		if _, exists := t.Inputs.Properties[name]; !exists {
+			return fmt.Errorf("tool %s: required input %s is not a declared property", t.Name, name)
+		}
+	}
+
+	for _, example := range t.Examples {
+		for name := range example.Input {
+			if _, exists := t.Inputs.Properties[name]; !exists {
+				return fmt.Errorf("tool %s: example %q uses undeclared input %s", t.Name, example.Name, name)
+			}
+		}
+	}
+
+	switch t.InputLocation {
+	case "", InputLocationQuery, InputLocationBody, InputLocationPath, InputLocationMixed:
+	default:
+		return fmt.Errorf("tool %s: unsupported input_location %q", t.Name, t.InputLocation)
+	}
+
+	if t.ToolProvider == nil {
+		return fmt.Errorf("tool %s: tool_provider is required", t.Name)
+	}
+
+	if providerType, _ := t.ToolProvider["provider_type"].(string); providerType == "http" {
+		if url, _ := t.ToolProvider["url"].(string); url == "" {
+			return fmt.Errorf("tool %s: tool_provider url is required", t.Name)
+		}
+
+		method, _ := t.ToolProvider["http_method"].(string)
+		if !ValidHTTPMethod(method) {
+			return fmt.Errorf("tool %s: unsupported http_method %q", t.Name, method)
+		}
+	}
+
+	return nil
+}
+
+// ApplyDefaults returns a copy of inputs with each missing top-level input
+// set to its property's default. Values the caller sent, including nil, are
+// kept, and properties without a default stay absent. inputs is not modified.
+func ApplyDefaults(tool Tool, inputs map[string]interface{}) map[string]interface{} {
+	applied := make(map[string]interface{}, len(inputs)+len(tool.Inputs.Properties))
+	for name, value := range inputs {
+		applied[name] = value
+	}
+
+	for name, property := range tool.Inputs.Properties {
+		if _, exists := applied[name]; exists || property.Default == nil {
+			continue
+		}
+		applied[name] = property.Default
+	}
+
+	return applied
 }
 
 // NewManual creates a new UTCP manual
@@ -46,8 +297,18 @@ func NewManual() *Manual {
 	}
 }
 
-// AddTool adds a tool to the manual
+// AddTool adds a tool to the manual. A tool provider without a provider_id
+// gets the tool's name as its id.
 func (m *Manual) AddTool(tool Tool) {
+	if id, _ := tool.ToolProvider["provider_id"].(string); id == "" && tool.ToolProvider != nil {
+		provider := make(map[string]interface{}, len(tool.ToolProvider)+1)
+		for key, value := range tool.ToolProvider {
+			provider[key] = value
+		}
+		provider["provider_id"] = tool.Name
+		tool.ToolProvider = provider
+	}
+
 	m.Tools = append(m.Tools, tool)
 }
 
@@ -60,15 +321,389 @@ func (m *Manual) ToJSON() (string, error) {
 	return string(data), nil
 }
 
-// HTTPProvider creates an HTTP provider configuration
-func HTTPProvider(name, url, method string, auth map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
+// ToCompactJSON converts the manual to JSON without indentation or newlines
+func (m *Manual) ToCompactJSON() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteJSON streams the manual to w as compact JSON, encoding one tool at a
+// time so large manuals are not buffered in memory. The output matches
+// ToCompactJSON.
+func (m *Manual) WriteJSON(w io.Writer) error {
+	if _, err := io.WriteString(w, `{"version":`); err != nil {
+		return err
+	}
+	if err := writeJSONValue(w, m.Version); err != nil {
+		return err
+	}
+
+	if m.Capabilities != nil {
+		if _, err := io.WriteString(w, `,"capabilities":`); err != nil {
+			return err
+		}
+		if err := writeJSONValue(w, m.Capabilities); err != nil {
+			return err
+		}
+	}
+
+	if m.Tools == nil {
+		_, err := io.WriteString(w, `,"tools":null}`)
+		return err
+	}
+
+	if _, err := io.WriteString(w, `,"tools":[`); err != nil {
+		return err
+	}
+	for i := range m.Tools {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := writeJSONValue(w, &m.Tools[i]); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]}")
+	return err
+}
+
+// ErrManualTooLarge is returned by CheckSize when a manual exceeds the limit
+var ErrManualTooLarge = errors.New("manual exceeds size limit")
+
+// CheckSize returns ErrManualTooLarge if the manual's compact JSON encoding
+// is longer than maxBytes. The manual is encoded one tool at a time into a
+// counter, so the check stops early and never buffers the whole manual.
+// maxBytes below 1 disables the check.
+func (m *Manual) CheckSize(maxBytes int64) error {
+	if maxBytes < 1 {
+		return nil
+	}
+	return m.WriteJSON(&limitCounter{remaining: maxBytes})
+}
+
+// limitCounter discards writes, failing once more than its remaining bytes
+// have been written
+type limitCounter struct {
+	remaining int64
+}
+
+func (l *limitCounter) Write(p []byte) (int, error) {
+	l.remaining -= int64(len(p))
+	if l.remaining < 0 {
+		return 0, ErrManualTooLarge
+	}
+	return len(p), nil
+}
+
+// writeJSONValue writes v to w as JSON without a trailing newline
+func writeJSONValue(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ToYAML converts the manual to YAML using the same field names as ToJSON
+func (m *Manual) ToYAML() ([]byte, error) {
+	doc, err := m.toGeneric()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// ToTOML converts the manual to TOML using the same field names as ToJSON
+func (m *Manual) ToTOML() ([]byte, error) {
+	doc, err := m.toGeneric()
+	if err != nil {
+		return nil, err
+	}
+	return toml.Marshal(doc)
+}
+
+// toGeneric converts the manual to maps and slices keyed by its JSON field
+// names, so every serializer honors the json tags and omitempty rules
+func (m *Manual) toGeneric() (map[string]interface{}, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return normalizeNumbers(doc).(map[string]interface{}), nil
+}
+
+// normalizeNumbers replaces json.Number values with int64 or float64 so
+// integers are not rendered as floats
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// WithoutToolProviders returns a copy of the manual with each tool's
+// tool_provider block removed, for clients that only need schemas
+func (m *Manual) WithoutToolProviders() *Manual {
+	tools := make([]Tool, len(m.Tools))
+	for i, tool := range m.Tools {
+		tool.ToolProvider = nil
+		tools[i] = tool
+	}
+
+	return &Manual{
+		Version:      m.Version,
+		Capabilities: m.Capabilities,
+		Tools:        tools,
+	}
+}
+
+// IsMutating reports whether calling the tool changes remote state, either
+// because it is flagged Mutating or because its provider uses a method other
+// than GET
+func (t Tool) IsMutating() bool {
+	if t.Mutating {
+		return true
+	}
+
+	method, _ := t.ToolProvider["http_method"].(string)
+	switch strings.ToUpper(method) {
+	case "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// WithoutMutating returns a copy of the manual with mutating tools removed
+func (m *Manual) WithoutMutating() *Manual {
+	tools := make([]Tool, 0, len(m.Tools))
+	for _, tool := range m.Tools {
+		if !tool.IsMutating() {
+			tools = append(tools, tool)
+		}
+	}
+
+	return &Manual{
+		Version:      m.Version,
+		Capabilities: m.Capabilities,
+		Tools:        tools,
+	}
+}
+
+// WithoutDeprecated returns a copy of the manual with deprecated tools removed
+func (m *Manual) WithoutDeprecated() *Manual {
+	tools := make([]Tool, 0, len(m.Tools))
+	for _, tool := range m.Tools {
+		if !tool.Deprecated {
+			tools = append(tools, tool)
+		}
+	}
+
+	return &Manual{
+		Version:      m.Version,
+		Capabilities: m.Capabilities,
+		Tools:        tools,
+	}
+}
+
+// Limit returns a copy of the manual holding at most n tools, taken in name
+// order so the same tools are kept on every call. Manuals with n or fewer
+// tools are returned unchanged.
+func (m *Manual) Limit(n int) *Manual {
+	if n < 0 || len(m.Tools) <= n {
+		return m
+	}
+
+	tools := make([]Tool, len(m.Tools))
+	copy(tools, m.Tools)
+	sort.SliceStable(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	return &Manual{
+		Version:      m.Version,
+		Capabilities: m.Capabilities,
+		Tools:        tools[:n],
+	}
+}
+
+// CanonicalJSON returns the compact JSON encoding of the manual with tools
+// sorted by name, so equal manuals always produce identical bytes
+func (m *Manual) CanonicalJSON() ([]byte, error) {
+	tools := make([]Tool, len(m.Tools))
+	copy(tools, m.Tools)
+	sort.SliceStable(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	return json.Marshal(&Manual{
+		Version:      m.Version,
+		Capabilities: m.Capabilities,
+		Tools:        tools,
+	})
+}
+
+// Checksum returns the hex-encoded SHA-256 of the canonical manual JSON
+func (m *Manual) Checksum() (string, error) {
+	data, err := m.CanonicalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Fingerprint returns the hex-encoded SHA-256 of the tool's JSON encoding.
+// encoding/json sorts map keys, so equal tools always share a fingerprint
+// regardless of how their properties or provider fields were inserted.
+func (t Tool) Fingerprint() string {
+	data, err := json.Marshal(t)
+	if err != nil {
+		// Unencodable tools never compare equal to anything else
+		return "unencodable:" + err.Error()
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// HTTPProviderOption customizes an HTTP provider configuration
+type HTTPProviderOption func(provider map[string]interface{})
+
+// WithTimeout sets timeout_ms so clients know how long to wait for a response
+func WithTimeout(timeout time.Duration) HTTPProviderOption {
+	return func(provider map[string]interface{}) {
+		provider["timeout_ms"] = timeout.Milliseconds()
+	}
+}
+
+// WithBodyField sets body_field so clients send the named input as the
+// entire request body, JSON-encoded on its own, rather than wrapping the
+// inputs in an object. Use it for endpoints that take a bare value, such as
+// a JSON string.
+func WithBodyField(name string) HTTPProviderOption {
+	return func(provider map[string]interface{}) {
+		provider["body_field"] = name
+	}
+}
+
+// WithHeaders sets headers clients should send on every call. Nothing is
+// emitted when headers is empty.
+func WithHeaders(headers map[string]string) HTTPProviderOption {
+	return func(provider map[string]interface{}) {
+		if len(headers) == 0 {
+			return
+		}
+
+		// Headers decoded from JSON, e.g. a remote catalog, are untyped
+		merged := make(map[string]string, len(headers))
+		switch existing := provider["headers"].(type) {
+		case map[string]string:
+			for name, value := range existing {
+				merged[name] = value
+			}
+		case map[string]interface{}:
+			for name, value := range existing {
+				if s, ok := value.(string); ok {
+					merged[name] = s
+				}
+			}
+		}
+		for name, value := range headers {
+			if _, exists := merged[name]; !exists {
+				merged[name] = value
+			}
+		}
+		provider["headers"] = merged
+	}
+}
+
+// ApplyHeaders adds headers to the provider of every tool, keeping any
+// header a tool already sets. Provider maps are copied so shared maps are
+// not modified.
+func ApplyHeaders(tools []Tool, headers map[string]string) []Tool {
+	if len(headers) == 0 {
+		return tools
+	}
+
+	for i := range tools {
+		provider := make(map[string]interface{}, len(tools[i].ToolProvider)+1)
+		for k, v := range tools[i].ToolProvider {
+			provider[k] = v
+		}
+		WithHeaders(headers)(provider)
+		tools[i].ToolProvider = provider
+	}
+
+	return tools
+}
+
+// HTTPProvider creates an HTTP provider configuration. The provider_id should
+// match the tool name; pass an empty name to have Manual.AddTool derive it.
+func HTTPProvider(name, url, method string, auth map[string]interface{}, opts ...HTTPProviderOption) map[string]interface{} {
+	provider := map[string]interface{}{
 		"provider_type": "http",
-		"provider_id":   name,
 		"url":           url,
 		"http_method":   method,
 		"auth":          auth,
 	}
+	if name != "" {
+		provider["provider_id"] = name
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	return provider
+}
+
+// HTTPProviderMultiAuth creates an HTTP provider configuration whose endpoint
+// accepts any of several auth schemes. The alternatives are listed under
+// auth_options so clients can pick one they have credentials for; auth is set
+// to the first for clients that only understand a single scheme.
+func HTTPProviderMultiAuth(name, url, method string, auths ...map[string]interface{}) map[string]interface{} {
+	var primary map[string]interface{}
+	if len(auths) > 0 {
+		primary = auths[0]
+	}
+
+	provider := HTTPProvider(name, url, method, primary)
+	provider["auth_options"] = auths
+
+	return provider
 }
 
 // APIKeyAuth creates API key authentication configuration
@@ -107,3 +742,19 @@ func PersonalTokenAuth(tokenEnv, headerName string) map[string]interface{} {
 		"header_name": headerName,
 	}
 }
+
+// TokenPlaceholder is replaced by the token variable in header value templates
+const TokenPlaceholder = "$TOKEN"
+
+// TemplatedTokenAuth creates personal token authentication whose header value
+// is rendered from valueTemplate, e.g. "Bearer $TOKEN"
+func TemplatedTokenAuth(tokenEnv, headerName, valueTemplate string) map[string]interface{} {
+	auth := PersonalTokenAuth(tokenEnv, headerName)
+	auth["header_value"] = strings.ReplaceAll(valueTemplate, TokenPlaceholder, "$"+tokenEnv)
+	return auth
+}
+
+// BearerAuth creates token authentication sent as "Authorization: Bearer <token>"
+func BearerAuth(tokenEnv string) map[string]interface{} {
+	return TemplatedTokenAuth(tokenEnv, "Authorization", "Bearer "+TokenPlaceholder)
+}
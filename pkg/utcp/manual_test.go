@@ -1,8 +1,14 @@
 package utcp
 
 import (
+	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewManual(t *testing.T) {
@@ -71,6 +77,531 @@ func TestToJSON(t *testing.T) {
 	}
 }
 
+func TestWriteJSON(t *testing.T) {
+	withCapabilities := serializationManual()
+	withCapabilities.Capabilities = &Capabilities{
+		ProviderTypes: []string{"jira"},
+		AuthTypes:     AuthTypes(),
+		ManualVersion: withCapabilities.Version,
+	}
+	withCapabilities.AddTool(Tool{Name: "second_tool", Inputs: Schema{Type: "object"}})
+
+	manuals := map[string]*Manual{
+		"empty":        NewManual(),
+		"nil tools":    {Version: "0.1.0"},
+		"single":       serializationManual(),
+		"capabilities": withCapabilities,
+	}
+
+	for name, manual := range manuals {
+		t.Run(name, func(t *testing.T) {
+			var streamed bytes.Buffer
+			if err := manual.WriteJSON(&streamed); err != nil {
+				t.Fatalf("WriteJSON failed: %v", err)
+			}
+
+			buffered, err := manual.ToJSON()
+			if err != nil {
+				t.Fatalf("ToJSON failed: %v", err)
+			}
+
+			var want, got bytes.Buffer
+			if err := json.Compact(&want, []byte(buffered)); err != nil {
+				t.Fatalf("Failed to compact buffered JSON: %v", err)
+			}
+			if err := json.Compact(&got, streamed.Bytes()); err != nil {
+				t.Fatalf("Failed to compact streamed JSON: %v", err)
+			}
+
+			if got.String() != want.String() {
+				t.Errorf("Expected streamed JSON %s, got %s", want.String(), got.String())
+			}
+		})
+	}
+}
+
+func TestToCompactJSON(t *testing.T) {
+	manual := serializationManual()
+
+	compact, err := manual.ToCompactJSON()
+	if err != nil {
+		t.Fatalf("ToCompactJSON failed: %v", err)
+	}
+
+	if strings.Contains(compact, "\n") {
+		t.Errorf("Expected compact JSON without newlines, got %s", compact)
+	}
+
+	var decoded Manual
+	if err := json.Unmarshal([]byte(compact), &decoded); err != nil {
+		t.Fatalf("Failed to decode compact JSON: %v", err)
+	}
+
+	want, err := manual.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	got, err := decoded.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("Expected compact JSON to decode to %s, got %s", want, got)
+	}
+
+	var streamed bytes.Buffer
+	if err := manual.WriteJSON(&streamed); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	if streamed.String() != compact {
+		t.Errorf("Expected streamed JSON %s, got %s", compact, streamed.String())
+	}
+}
+func TestSchemaAdditionalProperties(t *testing.T) {
+	data, err := json.Marshal(Schema{Type: "object"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "additionalProperties") {
+		t.Errorf("Expected additionalProperties to be omitted when unset, got %s", data)
+	}
+
+	data, err = json.Marshal(Schema{Type: "object", AdditionalProperties: Bool(false)})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"additionalProperties":false`) {
+		t.Errorf("Expected additionalProperties false, got %s", data)
+	}
+
+	var parsed Schema
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if parsed.AdditionalProperties == nil || *parsed.AdditionalProperties {
+		t.Errorf("Expected additionalProperties false after round trip, got %v", parsed.AdditionalProperties)
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	manual := serializationManual()
+	manual.AddTool(Tool{Name: "second_tool", Inputs: Schema{Type: "object"}})
+
+	compact, err := manual.ToCompactJSON()
+	if err != nil {
+		t.Fatalf("ToCompactJSON failed: %v", err)
+	}
+	size := int64(len(compact))
+
+	for _, max := range []int64{0, -1, size, size + 1} {
+		if err := manual.CheckSize(max); err != nil {
+			t.Errorf("Expected manual of %d bytes to fit limit %d, got %v", size, max, err)
+		}
+	}
+
+	for _, max := range []int64{1, size - 1} {
+		if err := manual.CheckSize(max); err != ErrManualTooLarge {
+			t.Errorf("Expected ErrManualTooLarge for limit %d, got %v", max, err)
+		}
+	}
+}
+
+func TestToolDependsOnJSON(t *testing.T) {
+	data, err := json.Marshal(Tool{Name: "do", DependsOn: []string{"get"}})
+	if err != nil {
+		t.Fatalf("Failed to marshal tool: %v", err)
+	}
+	if !strings.Contains(string(data), `"depends_on":["get"]`) {
+		t.Errorf("Expected depends_on in JSON, got %s", data)
+	}
+
+	data, err = json.Marshal(Tool{Name: "get"})
+	if err != nil {
+		t.Fatalf("Failed to marshal tool: %v", err)
+	}
+	if strings.Contains(string(data), "depends_on") {
+		t.Errorf("Expected depends_on to be omitted when empty, got %s", data)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	manual := NewManual()
+	for _, name := range []string{"delta", "alpha", "charlie", "bravo"} {
+		manual.AddTool(Tool{Name: name})
+	}
+
+	limited := manual.Limit(2)
+	if len(limited.Tools) != 2 || limited.Tools[0].Name != "alpha" || limited.Tools[1].Name != "bravo" {
+		t.Errorf("Expected [alpha bravo], got %v", limited.Tools)
+	}
+
+	if manual.Tools[0].Name != "delta" {
+		t.Error("Limit should not reorder the original manual")
+	}
+
+	if unchanged := manual.Limit(10); len(unchanged.Tools) != 4 {
+		t.Errorf("Expected all 4 tools under the limit, got %d", len(unchanged.Tools))
+	}
+}
+
+func TestWithDefaultRetry(t *testing.T) {
+	custom := &RetrySpec{MaxAttempts: 5, BackoffBaseMs: 1000}
+	tools := WithDefaultRetry([]Tool{
+		{Name: "get", ToolProvider: HTTPProvider("get", "https://example.com", "GET", nil)},
+		{Name: "create", ToolProvider: HTTPProvider("create", "https://example.com", "POST", nil)},
+		{Name: "custom", Retry: custom, ToolProvider: HTTPProvider("custom", "https://example.com", "GET", nil)},
+	})
+
+	if tools[0].Retry == nil || tools[0].Retry.MaxAttempts != 3 {
+		t.Errorf("Expected default retry spec on GET tool, got %+v", tools[0].Retry)
+	}
+
+	if tools[1].Retry != nil {
+		t.Errorf("Expected no retry spec on POST tool, got %+v", tools[1].Retry)
+	}
+
+	if tools[2].Retry != custom {
+		t.Errorf("Expected existing retry spec to be kept, got %+v", tools[2].Retry)
+	}
+
+	data, err := json.Marshal(tools[0])
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"retry":{"max_attempts":3,"backoff_base_ms":200,"retry_on":[502,503]}`) {
+		t.Errorf("Expected serialized retry spec, got %s", data)
+	}
+
+	data, err = json.Marshal(tools[1])
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "retry") {
+		t.Errorf("Expected retry to be omitted, got %s", data)
+	}
+}
+
+func serializationManual() *Manual {
+	manual := NewManual()
+	manual.AddTool(Tool{
+		Name:        "test_search",
+		Description: "Search things",
+		Inputs: Schema{
+			Type: "object",
+			Properties: map[string]Property{
+				"query":      {Type: "string", Description: "Search query"},
+				"maxResults": {Type: "integer", Default: 50},
+			},
+			Required: []string{"query"},
+		},
+		Outputs:      Schema{Type: "array"},
+		Tags:         []string{"test", "search"},
+		ToolProvider: HTTPProvider("test_search", "https://example.com/search", "GET", BearerAuth("TEST_TOKEN")),
+	})
+	return manual
+}
+
+// manualFromGeneric decodes a generic document back into a Manual
+func manualFromGeneric(t *testing.T, doc interface{}) *Manual {
+	t.Helper()
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to re-encode document: %v", err)
+	}
+
+	var manual Manual
+	if err := json.Unmarshal(data, &manual); err != nil {
+		t.Fatalf("Failed to decode manual: %v", err)
+	}
+	return &manual
+}
+
+func assertSameManual(t *testing.T, expected, actual *Manual) {
+	t.Helper()
+
+	want, _ := expected.CanonicalJSON()
+	got, _ := actual.CanonicalJSON()
+	if string(want) != string(got) {
+		t.Errorf("Round trip mismatch:\nexpected %s\ngot      %s", want, got)
+	}
+}
+
+func TestToYAMLRoundTrip(t *testing.T) {
+	manual := serializationManual()
+
+	data, err := manual.ToYAML()
+	if err != nil {
+		t.Fatalf("ToYAML failed: %v", err)
+	}
+
+	if !strings.Contains(string(data), "tool_provider:") {
+		t.Errorf("Expected YAML to use json field names, got:\n%s", data)
+	}
+
+	if !strings.Contains(string(data), "default: 50\n") {
+		t.Errorf("Expected integer default to stay an integer, got:\n%s", data)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+
+	assertSameManual(t, manual, manualFromGeneric(t, doc))
+}
+
+func TestToTOMLRoundTrip(t *testing.T) {
+	manual := serializationManual()
+
+	data, err := manual.ToTOML()
+	if err != nil {
+		t.Fatalf("ToTOML failed: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to parse TOML: %v", err)
+	}
+
+	assertSameManual(t, manual, manualFromGeneric(t, doc))
+}
+
+func TestToolExamplesJSON(t *testing.T) {
+	tool := Tool{
+		Name: "test_tool",
+		Examples: []ToolExample{
+			{
+				Name:   "Basic call",
+				Input:  map[string]interface{}{"query": "test"},
+				Output: map[string]interface{}{"count": 1},
+			},
+		},
+	}
+
+	data, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("Failed to marshal tool: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Failed to parse tool: %v", err)
+	}
+
+	examples, ok := parsed["examples"].([]interface{})
+	if !ok || len(examples) != 1 {
+		t.Fatalf("Expected 1 serialized example, got %v", parsed["examples"])
+	}
+
+	example := examples[0].(map[string]interface{})
+	for _, key := range []string{"name", "input", "output"} {
+		if _, exists := example[key]; !exists {
+			t.Errorf("Missing field '%s' in example", key)
+		}
+	}
+
+	// Tools without examples omit the field
+	data, _ = json.Marshal(Tool{Name: "bare"})
+	parsed = nil
+	json.Unmarshal(data, &parsed)
+	if _, exists := parsed["examples"]; exists {
+		t.Error("Expected 'examples' to be omitted when empty")
+	}
+}
+
+func TestCapabilitiesJSON(t *testing.T) {
+	manual := NewManual()
+
+	data, err := json.Marshal(manual)
+	if err != nil {
+		t.Fatalf("Failed to marshal manual: %v", err)
+	}
+
+	if strings.Contains(string(data), "capabilities") {
+		t.Errorf("Expected capabilities to be omitted when unset, got %s", data)
+	}
+
+	manual.Capabilities = &Capabilities{
+		ProviderTypes: []string{"jira"},
+		AuthTypes:     AuthTypes(),
+		ManualVersion: manual.Version,
+	}
+
+	data, err = json.Marshal(manual.WithoutToolProviders())
+	if err != nil {
+		t.Fatalf("Failed to marshal manual: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"capabilities":{"provider_types":["jira"]`) {
+		t.Errorf("Expected capabilities in manual JSON, got %s", data)
+	}
+}
+
+func TestWithoutToolProviders(t *testing.T) {
+	manual := NewManual()
+	manual.AddTool(Tool{
+		Name:         "test_tool",
+		ToolProvider: HTTPProvider("test", "https://internal.example.com", "GET", nil),
+	})
+
+	stripped := manual.WithoutToolProviders()
+
+	if stripped.Tools[0].ToolProvider != nil {
+		t.Error("Expected tool provider to be removed")
+	}
+
+	if manual.Tools[0].ToolProvider == nil {
+		t.Error("Expected original manual to keep its tool provider")
+	}
+
+	jsonStr, _ := stripped.ToJSON()
+	if strings.Contains(jsonStr, "tool_provider") {
+		t.Error("Expected 'tool_provider' to be absent from JSON")
+	}
+}
+
+func TestPropertyValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		property Property
+		wantErr  bool
+	}{
+		{"No enum", Property{Type: "string", Default: "anything"}, false},
+		{"No default", Property{Type: "string", Enum: []string{"a", "b"}}, false},
+		{"Default in enum", Property{Type: "string", Enum: []string{"a", "b"}, Default: "b"}, false},
+		{"Default outside enum", Property{Type: "string", Enum: []string{"a", "b"}, Default: "c"}, true},
+		{"Non-string default", Property{Type: "integer", Enum: []string{"1", "2"}, Default: 3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.property.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToolValidate(t *testing.T) {
+	valid := Tool{
+		Name: "valid_tool",
+		Inputs: Schema{
+			Type:       "object",
+			Properties: map[string]Property{"id": {Type: "string"}},
+			Required:   []string{"id"},
+		},
+		ToolProvider: HTTPProvider("valid_tool", "https://api.example.com/${id}", "GET", nil),
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid tool, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(tool *Tool)
+	}{
+		{"Missing name", func(tool *Tool) { tool.Name = "" }},
+		{"Missing inputs type", func(tool *Tool) { tool.Inputs.Type = "" }},
+		{"Undeclared required input", func(tool *Tool) { tool.Inputs.Required = []string{"other"} }},
+		{"Missing tool provider", func(tool *Tool) { tool.ToolProvider = nil }},
+		{"Example with undeclared input", func(tool *Tool) {
+			tool.Examples = []ToolExample{{Name: "bad", Input: map[string]interface{}{"other": 1}}}
+		}},
+		{"Missing url", func(tool *Tool) {
+			tool.ToolProvider = HTTPProvider("valid_tool", "", "GET", nil)
+		}},
+		{"Unsupported method", func(tool *Tool) {
+			tool.ToolProvider = HTTPProvider("valid_tool", "https://api.example.com", "TRACE", nil)
+		}},
+		{"Empty method", func(tool *Tool) {
+			tool.ToolProvider = HTTPProvider("valid_tool", "https://api.example.com", "", nil)
+		}},
+		{"Lowercase method", func(tool *Tool) {
+			tool.ToolProvider = HTTPProvider("valid_tool", "https://api.example.com", "get", nil)
+		}},
+		{"Default outside enum", func(tool *Tool) {
+			tool.Inputs.Properties = map[string]Property{
+				"id":    {Type: "string"},
+				"state": {Type: "string", Enum: []string{"open", "closed"}, Default: "all"},
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := valid
+			tool.Inputs.Required = append([]string(nil), valid.Inputs.Required...)
+			tt.mutate(&tool)
+
+			if err := tool.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestToolValidateMethods(t *testing.T) {
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+		tool := Tool{
+			Name: "delete_resource",
+			Inputs: Schema{
+				Type:       "object",
+				Properties: map[string]Property{"id": {Type: "string"}},
+				Required:   []string{"id"},
+			},
+			ToolProvider: HTTPProvider("delete_resource", "https://api.example.com/resources/${id}", method, nil),
+		}
+
+		if err := tool.Validate(); err != nil {
+			t.Errorf("Expected %s tool to be valid, got %v", method, err)
+		}
+
+		if tool.ToolProvider["http_method"] != method {
+			t.Errorf("Expected http_method %s, got %v", method, tool.ToolProvider["http_method"])
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	first := NewManual()
+	first.AddTool(Tool{Name: "b_tool", Inputs: Schema{Type: "object"}})
+	first.AddTool(Tool{Name: "a_tool", Inputs: Schema{Type: "object"}})
+
+	second := NewManual()
+	second.AddTool(Tool{Name: "a_tool", Inputs: Schema{Type: "object"}})
+	second.AddTool(Tool{Name: "b_tool", Inputs: Schema{Type: "object"}})
+
+	sum1, err := first.Checksum()
+	if err != nil {
+		t.Fatalf("Checksum failed: %v", err)
+	}
+
+	sum2, err := second.Checksum()
+	if err != nil {
+		t.Fatalf("Checksum failed: %v", err)
+	}
+
+	if sum1 != sum2 {
+		t.Errorf("Expected tool order not to affect checksum, got %s and %s", sum1, sum2)
+	}
+
+	// Canonicalization must not reorder the original manual
+	if first.Tools[0].Name != "b_tool" {
+		t.Errorf("Expected original tool order to be preserved, got %s first", first.Tools[0].Name)
+	}
+
+	second.AddTool(Tool{Name: "c_tool"})
+	sum3, _ := second.Checksum()
+	if sum3 == sum2 {
+		t.Error("Expected checksum to change when a tool is added")
+	}
+}
+
 func TestHTTPProvider(t *testing.T) {
 	auth := map[string]interface{}{
 		"auth_type": "basic",
@@ -106,6 +637,177 @@ func TestHTTPProvider(t *testing.T) {
 	}
 }
 
+func TestAddToolDerivesProviderID(t *testing.T) {
+	provider := HTTPProvider("", "https://api.example.com", "GET", nil)
+	if _, exists := provider["provider_id"]; exists {
+		t.Errorf("Expected no provider_id for empty name, got %v", provider["provider_id"])
+	}
+
+	manual := NewManual()
+	manual.AddTool(Tool{Name: "derived_tool", ToolProvider: provider})
+	manual.AddTool(Tool{Name: "explicit_tool", ToolProvider: HTTPProvider("custom_id", "https://api.example.com", "GET", nil)})
+
+	if id := manual.Tools[0].ToolProvider["provider_id"]; id != "derived_tool" {
+		t.Errorf("Expected provider_id 'derived_tool', got %v", id)
+	}
+
+	if id := manual.Tools[1].ToolProvider["provider_id"]; id != "custom_id" {
+		t.Errorf("Expected explicit provider_id 'custom_id', got %v", id)
+	}
+
+	if _, exists := provider["provider_id"]; exists {
+		t.Error("AddTool should not modify the caller's tool provider map")
+	}
+}
+
+func TestHTTPProviderMultiAuth(t *testing.T) {
+	provider := HTTPProviderMultiAuth("multi_auth_tool", "https://api.example.com", "GET",
+		PersonalTokenAuth("API_TOKEN", "PRIVATE-TOKEN"),
+		OAuth2Auth("CLIENT_ID", "CLIENT_SECRET", "TOKEN_URL"),
+	)
+
+	manual := NewManual()
+	manual.AddTool(Tool{Name: "multi_auth_tool", Inputs: Schema{Type: "object"}, ToolProvider: provider})
+
+	jsonStr, err := manual.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	var decoded struct {
+		Tools []struct {
+			ToolProvider struct {
+				Auth        map[string]interface{}   `json:"auth"`
+				AuthOptions []map[string]interface{} `json:"auth_options"`
+			} `json:"tool_provider"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &decoded); err != nil {
+		t.Fatalf("Failed to parse manual: %v", err)
+	}
+
+	options := decoded.Tools[0].ToolProvider.AuthOptions
+	if len(options) != 2 {
+		t.Fatalf("Expected 2 auth_options, got %d", len(options))
+	}
+
+	if options[0]["auth_type"] != "personal_token" {
+		t.Errorf("Expected first auth_type 'personal_token', got %v", options[0]["auth_type"])
+	}
+
+	if options[1]["auth_type"] != "oauth2" {
+		t.Errorf("Expected second auth_type 'oauth2', got %v", options[1]["auth_type"])
+	}
+
+	if decoded.Tools[0].ToolProvider.Auth["auth_type"] != "personal_token" {
+		t.Errorf("Expected auth to default to the first option, got %v", decoded.Tools[0].ToolProvider.Auth)
+	}
+}
+
+func TestHTTPProviderHeaders(t *testing.T) {
+	provider := HTTPProvider("test_provider", "https://api.example.com", "GET", nil)
+	if _, exists := provider["headers"]; exists {
+		t.Errorf("Expected no headers by default, got %v", provider["headers"])
+	}
+
+	headers := map[string]string{"X-Corp-Context": "engineering"}
+	provider = HTTPProvider("test_provider", "https://api.example.com", "GET", nil, WithHeaders(headers))
+
+	manual := NewManual()
+	manual.AddTool(Tool{Name: "test_provider", Inputs: Schema{Type: "object"}, ToolProvider: provider})
+
+	data, err := json.Marshal(manual)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded struct {
+		Tools []struct {
+			ToolProvider struct {
+				Headers map[string]string `json:"headers"`
+			} `json:"tool_provider"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to parse manual: %v", err)
+	}
+
+	if got := decoded.Tools[0].ToolProvider.Headers["X-Corp-Context"]; got != "engineering" {
+		t.Errorf("Expected X-Corp-Context 'engineering', got '%s'", got)
+	}
+}
+
+func TestApplyHeaders(t *testing.T) {
+	shared := HTTPProvider("", "https://api.example.com", "GET", nil,
+		WithHeaders(map[string]string{"X-Corp-Context": "tool"}))
+	tools := []Tool{{Name: "a", ToolProvider: shared}}
+
+	tools = ApplyHeaders(tools, map[string]string{"X-Corp-Context": "default", "X-Team": "platform"})
+
+	headers, _ := tools[0].ToolProvider["headers"].(map[string]string)
+	if headers["X-Corp-Context"] != "tool" {
+		t.Errorf("Expected tool header to take precedence, got '%s'", headers["X-Corp-Context"])
+	}
+
+	if headers["X-Team"] != "platform" {
+		t.Errorf("Expected default header X-Team 'platform', got '%s'", headers["X-Team"])
+	}
+
+	if original, _ := shared["headers"].(map[string]string); len(original) != 1 {
+		t.Errorf("ApplyHeaders should not modify the shared provider map, got %v", original)
+	}
+
+	decoded := HTTPProvider("", "https://api.example.com", "GET", nil)
+	decoded["headers"] = map[string]interface{}{"X-Corp-Context": "catalog"}
+	tools = ApplyHeaders([]Tool{{Name: "c", ToolProvider: decoded}}, map[string]string{"X-Corp-Context": "default"})
+
+	headers, _ = tools[0].ToolProvider["headers"].(map[string]string)
+	if headers["X-Corp-Context"] != "catalog" {
+		t.Errorf("Expected decoded tool header to take precedence, got '%s'", headers["X-Corp-Context"])
+	}
+
+	unchanged := ApplyHeaders([]Tool{{Name: "b", ToolProvider: HTTPProvider("", "https://api.example.com", "GET", nil)}}, nil)
+	if _, exists := unchanged[0].ToolProvider["headers"]; exists {
+		t.Error("Expected no headers when none are configured")
+	}
+}
+
+func TestHTTPProviderBodyField(t *testing.T) {
+	provider := HTTPProvider("add_watcher", "https://api.example.com/watchers", "POST", nil)
+	if _, exists := provider["body_field"]; exists {
+		t.Errorf("Expected no body_field by default, got %v", provider["body_field"])
+	}
+
+	provider = HTTPProvider("add_watcher", "https://api.example.com/watchers", "POST", nil, WithBodyField("username"))
+	if provider["body_field"] != "username" {
+		t.Errorf("Expected body_field 'username', got %v", provider["body_field"])
+	}
+}
+
+func TestHTTPProviderTimeout(t *testing.T) {
+	provider := HTTPProvider("test_provider", "https://api.example.com", "GET", nil)
+	if _, exists := provider["timeout_ms"]; exists {
+		t.Errorf("Expected no timeout_ms by default, got %v", provider["timeout_ms"])
+	}
+
+	provider = HTTPProvider("test_provider", "https://api.example.com", "GET", nil, WithTimeout(30*time.Second))
+	if provider["timeout_ms"] != int64(30000) {
+		t.Errorf("Expected timeout_ms 30000, got %v", provider["timeout_ms"])
+	}
+
+	manual := NewManual()
+	manual.AddTool(Tool{Name: "slow_export", Inputs: Schema{Type: "object"}, ToolProvider: provider})
+
+	jsonStr, err := manual.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	if !strings.Contains(jsonStr, `"timeout_ms": 30000`) {
+		t.Errorf("Expected timeout_ms in JSON, got %s", jsonStr)
+	}
+}
+
 func TestAPIKeyAuth(t *testing.T) {
 	auth := APIKeyAuth("API_KEY", "X-API-Key")
 
@@ -174,6 +876,61 @@ func TestPersonalTokenAuth(t *testing.T) {
 	}
 }
 
+func TestPersonalTokenAuthHasNoTemplate(t *testing.T) {
+	auth := PersonalTokenAuth("GITLAB_TOKEN", "PRIVATE-TOKEN")
+
+	if _, exists := auth["header_value"]; exists {
+		t.Errorf("Expected no header_value for plain token auth, got %v", auth["header_value"])
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	auth := BearerAuth("API_TOKEN")
+
+	if auth["auth_type"] != "personal_token" {
+		t.Errorf("Expected auth_type 'personal_token', got %v", auth["auth_type"])
+	}
+
+	if auth["token"] != "$API_TOKEN" {
+		t.Errorf("Expected token '$API_TOKEN', got %v", auth["token"])
+	}
+
+	if auth["header_name"] != "Authorization" {
+		t.Errorf("Expected header_name 'Authorization', got %v", auth["header_name"])
+	}
+
+	if auth["header_value"] != "Bearer $API_TOKEN" {
+		t.Errorf("Expected header_value 'Bearer $API_TOKEN', got %v", auth["header_value"])
+	}
+}
+
+func TestTemplatedTokenAuthCustomHeader(t *testing.T) {
+	auth := TemplatedTokenAuth("SERVICE_TOKEN", "X-Service-Auth", "Token $TOKEN")
+
+	if auth["header_name"] != "X-Service-Auth" {
+		t.Errorf("Expected header_name 'X-Service-Auth', got %v", auth["header_name"])
+	}
+
+	if auth["header_value"] != "Token $SERVICE_TOKEN" {
+		t.Errorf("Expected header_value 'Token $SERVICE_TOKEN', got %v", auth["header_value"])
+	}
+
+	// The rendered value must survive serialization unchanged
+	data, err := json.Marshal(auth)
+	if err != nil {
+		t.Fatalf("Failed to marshal auth: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Failed to parse auth: %v", err)
+	}
+
+	if parsed["header_value"] != "Token $SERVICE_TOKEN" {
+		t.Errorf("Expected serialized header_value 'Token $SERVICE_TOKEN', got %v", parsed["header_value"])
+	}
+}
+
 func TestSchemaValidation(t *testing.T) {
 	schema := Schema{
 		Type: "object",
@@ -215,3 +972,172 @@ func TestSchemaValidation(t *testing.T) {
 		t.Errorf("Expected 2 enum values, got %d", len(schema.Properties["status"].Enum))
 	}
 }
+
+func TestToolIsMutating(t *testing.T) {
+	tests := []struct {
+		name string
+		tool Tool
+		want bool
+	}{
+		{"get", Tool{ToolProvider: HTTPProvider("", "https://example.com", "GET", nil)}, false},
+		{"post", Tool{ToolProvider: HTTPProvider("", "https://example.com", "POST", nil)}, true},
+		{"put", Tool{ToolProvider: HTTPProvider("", "https://example.com", "PUT", nil)}, true},
+		{"patch", Tool{ToolProvider: HTTPProvider("", "https://example.com", "PATCH", nil)}, true},
+		{"delete", Tool{ToolProvider: HTTPProvider("", "https://example.com", "DELETE", nil)}, true},
+		{"flagged", Tool{Mutating: true, ToolProvider: HTTPProvider("", "https://example.com", "GET", nil)}, true},
+		{"no provider", Tool{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.tool.IsMutating(); got != tt.want {
+			t.Errorf("%s: expected IsMutating %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWithoutMutating(t *testing.T) {
+	manual := NewManual()
+	manual.AddTool(Tool{Name: "get_item", ToolProvider: HTTPProvider("", "https://example.com", "GET", nil)})
+	manual.AddTool(Tool{Name: "create_item", ToolProvider: HTTPProvider("", "https://example.com", "POST", nil)})
+
+	filtered := manual.WithoutMutating()
+
+	if len(filtered.Tools) != 1 || filtered.Tools[0].Name != "get_item" {
+		t.Errorf("Expected only get_item, got %v", filtered.Tools)
+	}
+
+	if len(manual.Tools) != 2 {
+		t.Error("WithoutMutating should not modify the original manual")
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	tool := Tool{
+		Name: "test_search",
+		Inputs: Schema{
+			Type: "object",
+			Properties: map[string]Property{
+				"query":      {Type: "string"},
+				"maxResults": {Type: "integer", Default: 50},
+				"order":      {Type: "string", Default: "asc"},
+			},
+		},
+	}
+
+	inputs := map[string]interface{}{"query": "bugs", "order": "desc"}
+	applied := ApplyDefaults(tool, inputs)
+
+	if applied["maxResults"] != 50 {
+		t.Errorf("Expected default maxResults 50, got %v", applied["maxResults"])
+	}
+
+	if applied["order"] != "desc" {
+		t.Errorf("Expected provided order 'desc' to be preserved, got %v", applied["order"])
+	}
+
+	if applied["query"] != "bugs" {
+		t.Errorf("Expected provided query 'bugs', got %v", applied["query"])
+	}
+
+	if len(applied) != 3 {
+		t.Errorf("Expected 3 inputs, got %d", len(applied))
+	}
+
+	if _, exists := inputs["maxResults"]; exists {
+		t.Error("ApplyDefaults should not modify the given inputs")
+	}
+
+	applied = ApplyDefaults(tool, nil)
+	if _, exists := applied["query"]; exists {
+		t.Error("Expected property without a default to stay absent")
+	}
+
+	if applied["maxResults"] != 50 || applied["order"] != "asc" {
+		t.Errorf("Expected defaults for nil inputs, got %v", applied)
+	}
+}
+
+func TestToolFingerprint(t *testing.T) {
+	first := Tool{
+		Name:        "test_search",
+		Description: "Search things",
+		Inputs: Schema{
+			Type:       "object",
+			Properties: map[string]Property{},
+		},
+		ToolProvider: map[string]interface{}{},
+	}
+	second := first
+	second.Inputs.Properties = map[string]Property{}
+	second.ToolProvider = map[string]interface{}{}
+
+	// Insert the same entries in opposite orders
+	first.Inputs.Properties["query"] = Property{Type: "string"}
+	first.Inputs.Properties["limit"] = Property{Type: "integer", Default: 50}
+	second.Inputs.Properties["limit"] = Property{Type: "integer", Default: 50}
+	second.Inputs.Properties["query"] = Property{Type: "string"}
+
+	first.ToolProvider["url"] = "https://example.com/search"
+	first.ToolProvider["http_method"] = "GET"
+	second.ToolProvider["http_method"] = "GET"
+	second.ToolProvider["url"] = "https://example.com/search"
+
+	fingerprint := first.Fingerprint()
+	if len(fingerprint) != 64 {
+		t.Fatalf("Expected 64 character hex fingerprint, got '%s'", fingerprint)
+	}
+
+	if second.Fingerprint() != fingerprint {
+		t.Errorf("Expected equal tools to share a fingerprint, got %s and %s", fingerprint, second.Fingerprint())
+	}
+
+	second.Description = "Search other things"
+	if second.Fingerprint() == fingerprint {
+		t.Error("Expected description change to change the fingerprint")
+	}
+}
+
+func TestInferInputLocation(t *testing.T) {
+	inputs := Schema{
+		Type: "object",
+		Properties: map[string]Property{
+			"id":    {Type: "string"},
+			"title": {Type: "string"},
+		},
+	}
+	idOnly := Schema{Type: "object", Properties: map[string]Property{"id": {Type: "string"}}}
+
+	tests := []struct {
+		name   string
+		tool   Tool
+		expect string
+	}{
+		{"get", Tool{Inputs: inputs, ToolProvider: HTTPProvider("", "https://example.com/items", "GET", nil)}, InputLocationQuery},
+		{"delete", Tool{Inputs: inputs, ToolProvider: HTTPProvider("", "https://example.com/items", "DELETE", nil)}, InputLocationQuery},
+		{"post", Tool{Inputs: inputs, ToolProvider: HTTPProvider("", "https://example.com/items", "POST", nil)}, InputLocationBody},
+		{"put", Tool{Inputs: inputs, ToolProvider: HTTPProvider("", "https://example.com/items", "PUT", nil)}, InputLocationBody},
+		{"path only", Tool{Inputs: idOnly, ToolProvider: HTTPProvider("", "https://example.com/items/${id}", "GET", nil)}, InputLocationPath},
+		{"mixed", Tool{Inputs: inputs, ToolProvider: HTTPProvider("", "https://example.com/items/${id}", "PUT", nil)}, InputLocationMixed},
+		{"no provider", Tool{Inputs: inputs}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := InferInputLocation(tt.tool); got != tt.expect {
+			t.Errorf("%s: expected input location '%s', got '%s'", tt.name, tt.expect, got)
+		}
+	}
+
+	tools := WithInputLocations([]Tool{tests[0].tool, {InputLocation: InputLocationBody, ToolProvider: tests[0].tool.ToolProvider}})
+	if tools[0].InputLocation != InputLocationQuery {
+		t.Errorf("Expected inferred 'query', got '%s'", tools[0].InputLocation)
+	}
+	if tools[1].InputLocation != InputLocationBody {
+		t.Errorf("Expected explicit 'body' to be kept, got '%s'", tools[1].InputLocation)
+	}
+
+	invalid := serializationManual().Tools[0]
+	invalid.InputLocation = "header"
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected error for unsupported input_location, got nil")
+	}
+}
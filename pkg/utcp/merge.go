@@ -0,0 +1,165 @@
+package utcp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CollisionPolicy controls how MergeManualsWithPolicy handles tools with the
+// same name in more than one manual
+type CollisionPolicy string
+
+const (
+	// CollisionError fails the merge on the first duplicate tool name
+	CollisionError CollisionPolicy = "error"
+	// CollisionPrefix renames duplicates from later manuals to
+	// "manual<N>_<name>", where N is the manual's 1-based position
+	CollisionPrefix CollisionPolicy = "prefix"
+)
+
+// MergeManuals combines the tools of several manuals, failing if two manuals
+// declare a tool with the same name
+func MergeManuals(manuals ...*Manual) (*Manual, error) {
+	return MergeManualsWithPolicy(CollisionError, manuals...)
+}
+
+// MergeManualsWithPolicy combines the tools of several manuals in order. The
+// merged manual uses the lowest version among the inputs, since that is the
+// one every source can be read as, and the union of their capabilities. Nil
+// manuals are skipped.
+func MergeManualsWithPolicy(policy CollisionPolicy, manuals ...*Manual) (*Manual, error) {
+	if policy != CollisionError && policy != CollisionPrefix {
+		return nil, fmt.Errorf("unknown collision policy %q", policy)
+	}
+
+	merged := NewManual()
+	seen := make(map[string]bool)
+	version := ""
+
+	var capabilities *Capabilities
+
+	for i, manual := range manuals {
+		if manual == nil {
+			continue
+		}
+
+		if version == "" || compareVersions(manual.Version, version) < 0 {
+			version = manual.Version
+		}
+
+		if manual.Capabilities != nil {
+			capabilities = mergeCapabilities(capabilities, manual.Capabilities)
+		}
+
+		for _, tool := range manual.Tools {
+			if seen[tool.Name] {
+				if policy == CollisionError {
+					return nil, fmt.Errorf("tool %s is declared by more than one manual", tool.Name)
+				}
+
+				tool = renameTool(tool, fmt.Sprintf("manual%d_%s", i+1, tool.Name))
+				if seen[tool.Name] {
+					return nil, fmt.Errorf("tool %s is declared by more than one manual", tool.Name)
+				}
+			}
+
+			seen[tool.Name] = true
+			merged.Tools = append(merged.Tools, tool)
+		}
+	}
+
+	if version != "" {
+		merged.Version = version
+	}
+
+	if capabilities != nil {
+		capabilities.ManualVersion = merged.Version
+		merged.Capabilities = capabilities
+	}
+
+	return merged, nil
+}
+
+// renameTool returns a copy of tool with a new name. A provider_id that
+// matched the old name is updated to match the new one.
+func renameTool(tool Tool, name string) Tool {
+	if id, _ := tool.ToolProvider["provider_id"].(string); id == tool.Name {
+		provider := make(map[string]interface{}, len(tool.ToolProvider))
+		for key, value := range tool.ToolProvider {
+			provider[key] = value
+		}
+		provider["provider_id"] = name
+		tool.ToolProvider = provider
+	}
+
+	tool.Name = name
+	return tool
+}
+
+// mergeCapabilities returns the union of two capability sets in sorted order
+func mergeCapabilities(a, b *Capabilities) *Capabilities {
+	if a == nil {
+		a = &Capabilities{}
+	}
+
+	return &Capabilities{
+		ProviderTypes: union(a.ProviderTypes, b.ProviderTypes),
+		AuthTypes:     union(a.AuthTypes, b.AuthTypes),
+	}
+}
+
+// union returns the sorted distinct values of both slices
+func union(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, value := range append(append([]string(nil), a...), b...) {
+		set[value] = true
+	}
+
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	return values
+}
+
+// compareVersions compares dotted numeric versions such as "0.1.0". Parts
+// that are not numbers compare as strings.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		if aPart == "" {
+			aNum, aErr = 0, nil
+		}
+		if bPart == "" {
+			bNum, bErr = 0, nil
+		}
+
+		switch {
+		case aErr == nil && bErr == nil && aNum != bNum:
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && aPart != bPart:
+			return strings.Compare(aPart, bPart)
+		}
+	}
+
+	return 0
+}
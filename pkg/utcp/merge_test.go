@@ -0,0 +1,107 @@
+package utcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeManuals(t *testing.T) {
+	first := NewManual()
+	first.Capabilities = &Capabilities{ProviderTypes: []string{"jira"}, AuthTypes: []string{"basic"}}
+	first.AddTool(Tool{Name: "jira_get_issue", ToolProvider: HTTPProvider("", "https://jira", "GET", nil)})
+
+	second := NewManual()
+	second.Version = "0.0.9"
+	second.Capabilities = &Capabilities{ProviderTypes: []string{"gitlab", "jira"}, AuthTypes: []string{"personal_token"}}
+	second.AddTool(Tool{Name: "gitlab_get_project", ToolProvider: HTTPProvider("", "https://gitlab", "GET", nil)})
+
+	merged, err := MergeManuals(first, nil, second)
+	if err != nil {
+		t.Fatalf("MergeManuals failed: %v", err)
+	}
+
+	if len(merged.Tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(merged.Tools))
+	}
+
+	if merged.Tools[0].Name != "jira_get_issue" || merged.Tools[1].Name != "gitlab_get_project" {
+		t.Errorf("Expected tools in manual order, got %s, %s", merged.Tools[0].Name, merged.Tools[1].Name)
+	}
+
+	if merged.Version != "0.0.9" {
+		t.Errorf("Expected lowest version '0.0.9', got '%s'", merged.Version)
+	}
+
+	if merged.Capabilities == nil {
+		t.Fatal("Expected merged capabilities")
+	}
+
+	if strings.Join(merged.Capabilities.ProviderTypes, ",") != "gitlab,jira" {
+		t.Errorf("Expected provider types gitlab,jira, got %v", merged.Capabilities.ProviderTypes)
+	}
+
+	if strings.Join(merged.Capabilities.AuthTypes, ",") != "basic,personal_token" {
+		t.Errorf("Expected auth types basic,personal_token, got %v", merged.Capabilities.AuthTypes)
+	}
+
+	if merged.Capabilities.ManualVersion != "0.0.9" {
+		t.Errorf("Expected capabilities manual version '0.0.9', got '%s'", merged.Capabilities.ManualVersion)
+	}
+}
+
+func TestMergeManualsCollision(t *testing.T) {
+	first := NewManual()
+	first.AddTool(Tool{Name: "jira_get_issue", ToolProvider: HTTPProvider("", "https://jira-a", "GET", nil)})
+
+	second := NewManual()
+	second.AddTool(Tool{Name: "jira_get_issue", ToolProvider: HTTPProvider("", "https://jira-b", "GET", nil)})
+
+	if _, err := MergeManuals(first, second); err == nil {
+		t.Error("Expected error for duplicate tool name, got nil")
+	}
+
+	merged, err := MergeManualsWithPolicy(CollisionPrefix, first, second)
+	if err != nil {
+		t.Fatalf("MergeManualsWithPolicy failed: %v", err)
+	}
+
+	if len(merged.Tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(merged.Tools))
+	}
+
+	renamed := merged.Tools[1]
+	if renamed.Name != "manual2_jira_get_issue" {
+		t.Errorf("Expected renamed tool 'manual2_jira_get_issue', got '%s'", renamed.Name)
+	}
+
+	if renamed.ToolProvider["provider_id"] != "manual2_jira_get_issue" {
+		t.Errorf("Expected provider_id to follow the rename, got %v", renamed.ToolProvider["provider_id"])
+	}
+
+	if second.Tools[0].ToolProvider["provider_id"] != "jira_get_issue" {
+		t.Error("Merging should not modify the source manual")
+	}
+
+	if _, err := MergeManualsWithPolicy("rename", first, second); err == nil {
+		t.Error("Expected error for unknown collision policy, got nil")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.1.0", "0.1.0", 0},
+		{"0.1.0", "0.2.0", -1},
+		{"0.10.0", "0.9.0", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.0.0-beta", "1.0.0-alpha", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package utcp
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Weights for a query word matching a word in each tool field
+const (
+	searchNameWeight        = 3.0
+	searchTagWeight         = 2.0
+	searchDescriptionWeight = 1.0
+)
+
+// Bonuses for the whole query appearing verbatim in a tool field
+const (
+	searchNameSubstringBonus        = 4.0
+	searchDescriptionSubstringBonus = 2.0
+)
+
+// SearchResult is a tool matched by Search and its relevance score
+type SearchResult struct {
+	Tool  Tool
+	Score float64
+}
+
+// Search ranks the manual's tools against a free-text query such as
+// "close a ticket" and returns at most limit matches, best first. Each query
+// word scores for every field whose words contain it, with half credit for
+// prefix matches like "request" and "requests", and the whole query earns a
+// bonus when it appears verbatim in the name or description. Tools that
+// score zero are omitted, and limit below 1 returns every match.
+func (m *Manual) Search(query string, limit int) []SearchResult {
+	words := searchWords(query)
+	if len(words) == 0 {
+		return nil
+	}
+	phrase := strings.Join(words, " ")
+
+	var results []SearchResult
+	for _, tool := range m.Tools {
+		if score := searchScore(tool, words, phrase); score > 0 {
+			results = append(results, SearchResult{Tool: tool, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Tool.Name < results[j].Tool.Name
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// searchScore scores one tool against the query words and phrase
+func searchScore(tool Tool, words []string, phrase string) float64 {
+	name := searchWords(tool.Name)
+	description := searchWords(tool.Description)
+
+	var tags []string
+	for _, tag := range tool.Tags {
+		tags = append(tags, searchWords(tag)...)
+	}
+
+	var score float64
+	for _, word := range words {
+		score += searchNameWeight * wordMatch(word, name)
+		score += searchTagWeight * wordMatch(word, tags)
+		score += searchDescriptionWeight * wordMatch(word, description)
+	}
+
+	if score == 0 {
+		return 0
+	}
+
+	if strings.Contains(strings.Join(name, " "), phrase) {
+		score += searchNameSubstringBonus
+	}
+	if strings.Contains(strings.Join(description, " "), phrase) {
+		score += searchDescriptionSubstringBonus
+	}
+
+	return score
+}
+
+// wordMatch returns 1 when word is among candidates, 0.5 when one is a
+// prefix of the other and the shorter has at least three letters, and 0
+// otherwise
+func wordMatch(word string, candidates []string) float64 {
+	best := 0.0
+	for _, candidate := range candidates {
+		if candidate == word {
+			return 1
+		}
+
+		shorter, longer := word, candidate
+		if len(shorter) > len(longer) {
+			shorter, longer = longer, shorter
+		}
+		if len(shorter) >= 3 && strings.HasPrefix(longer, shorter) {
+			best = 0.5
+		}
+	}
+	return best
+}
+
+// searchWords lowercases s and splits it into words on anything that is not
+// a letter or digit, so "gitlab_list_merge_requests" and "provider:gitlab"
+// split like prose. Single-character words such as "a" are dropped.
+func searchWords(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := fields[:0]
+	for _, field := range fields {
+		if len(field) > 1 {
+			words = append(words, field)
+		}
+	}
+	return words
+}
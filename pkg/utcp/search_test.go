@@ -0,0 +1,90 @@
+package utcp
+
+import (
+	"testing"
+)
+
+func searchManual() *Manual {
+	manual := NewManual()
+	manual.AddTool(Tool{Name: "jira_search_issues", Description: "Search for Jira issues using JQL", Tags: []string{"jira", "search", "issues"}})
+	manual.AddTool(Tool{Name: "jira_update_issue", Description: "Update an existing Jira issue, e.g. to close it", Tags: []string{"jira", "issue", "update"}})
+	manual.AddTool(Tool{Name: "gitlab_list_merge_requests", Description: "List merge requests in a GitLab project", Tags: []string{"gitlab", "merge_request", "list"}})
+	manual.AddTool(Tool{Name: "gitlab_create_merge_request", Description: "Create a merge request in a GitLab project", Tags: []string{"gitlab", "merge_request", "create"}})
+	manual.AddTool(Tool{Name: "wiki_get_page", Description: "Get a Confluence page", Tags: []string{"wiki", "page"}})
+	return manual
+}
+
+func TestSearch(t *testing.T) {
+	results := searchManual().Search("merge request", 0)
+
+	if len(results) < 2 {
+		t.Fatalf("Expected at least 2 results, got %d", len(results))
+	}
+
+	for _, result := range results[:2] {
+		if result.Tool.Name != "gitlab_list_merge_requests" && result.Tool.Name != "gitlab_create_merge_request" {
+			t.Errorf("Expected GitLab merge request tools first, got %s", result.Tool.Name)
+		}
+	}
+
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("Expected results in descending score order, got %v after %v", results[i].Score, results[i-1].Score)
+		}
+	}
+
+	for _, result := range results {
+		if result.Tool.Name == "wiki_get_page" {
+			t.Error("Expected unrelated tool to be omitted")
+		}
+	}
+}
+
+func TestSearchPrefixMatch(t *testing.T) {
+	results := searchManual().Search("close a ticket", 0)
+
+	if len(results) != 1 || results[0].Tool.Name != "jira_update_issue" {
+		t.Fatalf("Expected only jira_update_issue for 'close a ticket', got %v", results)
+	}
+
+	exact := searchManual().Search("page", 0)
+	plural := searchManual().Search("pages", 0)
+
+	if len(plural) != 1 || plural[0].Tool.Name != "wiki_get_page" {
+		t.Fatalf("Expected 'pages' to match wiki_get_page, got %v", plural)
+	}
+
+	if plural[0].Score >= exact[0].Score {
+		t.Errorf("Expected prefix match to score below exact match, got %v and %v", plural[0].Score, exact[0].Score)
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+	results := searchManual().Search("gitlab jira", 2)
+
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+}
+
+func TestSearchEmptyQuery(t *testing.T) {
+	for _, query := range []string{"", "  ", "a ?"} {
+		if results := searchManual().Search(query, 0); len(results) != 0 {
+			t.Errorf("Expected no results for %q, got %d", query, len(results))
+		}
+	}
+}
+
+func TestSearchWords(t *testing.T) {
+	words := searchWords("gitlab_list_merge_requests provider:GitLab a")
+	want := []string{"gitlab", "list", "merge", "requests", "provider", "gitlab"}
+
+	if len(words) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, words)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("Expected word %d to be %s, got %s", i, want[i], words[i])
+		}
+	}
+}